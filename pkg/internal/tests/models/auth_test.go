@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// authRecorder records the Authorization header of every watsonx request
+type authRecorder struct {
+	authorizations []string
+}
+
+func (a *authRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.authorizations = append(a.authorizations, r.Header.Get("Authorization"))
+	okHandler(w, r)
+}
+
+func newAuthServer(t *testing.T, recorder *authRecorder) *httptest.Server {
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCP4DAuthenticator(t *testing.T) {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix())))
+	jwt := "eyJhbGciOiJSUzI1NiJ9." + claims + ".signature"
+
+	var authorize map[string]string
+	recorder := &authRecorder{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(wx.CP4DAuthorizePath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&authorize)
+		w.Write([]byte(`{"_messageCode_":"200","message":"Success","token":"` + jwt + `"}`))
+	})
+	mux.Handle("/", recorder)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithAuthenticator(wx.NewCP4DAPIKeyAuthenticator(server.URL, "admin", "cp4d-api-key")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if authorize["username"] != "admin" || authorize["api_key"] != "cp4d-api-key" || authorize["password"] != "" {
+		t.Fatalf("Expected username and API key to be sent, but got %v", authorize)
+	}
+	if recorder.authorizations[0] != "Bearer "+jwt {
+		t.Fatalf("Expected the CP4D token to be sent, but got %s", recorder.authorizations[0])
+	}
+}
+
+func TestBearerTokenAuthenticator(t *testing.T) {
+	recorder := &authRecorder{}
+	server := newAuthServer(t, recorder)
+
+	client, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithAuthenticator(wx.NewBearerTokenAuthenticator("my-token")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if recorder.authorizations[0] != "Bearer my-token" {
+		t.Fatalf("Expected the bearer token to be sent, but got %s", recorder.authorizations[0])
+	}
+}
+
+func TestNoAuthAuthenticator(t *testing.T) {
+	recorder := &authRecorder{}
+	server := newAuthServer(t, recorder)
+
+	client, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithAuthenticator(wx.NewNoAuthAuthenticator()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if recorder.authorizations[0] != "" {
+		t.Fatalf("Expected no Authorization header, but got %s", recorder.authorizations[0])
+	}
+}
+
+// countingAuthenticator is a custom authenticator issuing short-lived tokens
+type countingAuthenticator struct {
+	calls int
+}
+
+func (a *countingAuthenticator) Authenticate(ctx context.Context, client wx.Doer) (wx.IAMToken, error) {
+	a.calls++
+	return wx.NewIAMToken(fmt.Sprintf("custom-%d", a.calls), time.Now().Add(time.Hour)), nil
+}
+
+func TestCustomAuthenticator(t *testing.T) {
+	recorder := &authRecorder{}
+	server := newAuthServer(t, recorder)
+
+	authenticator := &countingAuthenticator{}
+	client, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithAuthenticator(authenticator),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+			t.Fatalf("Expected no error, but got an error: %v", err)
+		}
+	}
+
+	if authenticator.calls != 1 {
+		t.Fatalf("Expected the token to be cached, but the authenticator was called %d times", authenticator.calls)
+	}
+	if recorder.authorizations[2] != "Bearer custom-1" {
+		t.Fatalf("Expected the custom token to be sent, but got %s", recorder.authorizations[2])
+	}
+}
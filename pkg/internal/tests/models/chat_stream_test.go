@@ -0,0 +1,157 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func newChatStreamServer(t *testing.T, events ...string) *wx.Client {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+
+		if r.URL.Path != wx.ChatStreamEndpoint {
+			t.Errorf("Expected path %s, but got %s", wx.ChatStreamEndpoint, r.URL.Path)
+		}
+		if accept := r.Header.Get("Accept"); accept != "text/event-stream" {
+			t.Errorf("Expected event stream to be accepted, but got %s", accept)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, event := range events {
+			fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", i+1, event)
+		}
+	})
+
+	return getTestClient(t, server)
+}
+
+func TestChatStream(t *testing.T) {
+	client := newChatStreamServer(t,
+		`{"id":"chat-1","model_id":"m","choices":[{"index":0,"delta":{"role":"assistant","content":"I am"}}]}`,
+		`{"id":"chat-1","model_id":"m","choices":[{"index":0,"delta":{"content":" an AI."}}]}`,
+		`{"id":"chat-1","model_id":"m","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":4,"total_tokens":9}}`,
+	)
+
+	stream, err := client.ChatStream(ChatModelId, []wx.ChatMessage{wx.UserMessage("Who are you?")})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	accumulator := wx.NewChatStreamAccumulator()
+	deltas := ""
+	chunks := 0
+	for stream.Next() {
+		chunk := stream.Current()
+		chunks++
+		deltas += chunk.Choices[0].Delta.Content
+		accumulator.Add(chunk)
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expected no stream error, but got %v", err)
+	}
+	if chunks != 3 {
+		t.Fatalf("Expected 3 chunks, but got %d", chunks)
+	}
+	if deltas != "I am an AI." {
+		t.Fatalf("Expected deltas to form 'I am an AI.', but got %s", deltas)
+	}
+
+	result := accumulator.Result()
+	if len(result.Choices) != 1 {
+		t.Fatalf("Expected 1 choice, but got %d", len(result.Choices))
+	}
+	if result.Choices[0].Message.Content != "I am an AI." || result.Choices[0].FinishReason != wx.FinishStop {
+		t.Fatalf("Expected accumulated message, but got %+v", result.Choices[0])
+	}
+	if result.Usage.TotalTokens != 9 {
+		t.Fatalf("Expected 9 total tokens, but got %d", result.Usage.TotalTokens)
+	}
+}
+
+func TestChatStreamAccumulatesToolCalls(t *testing.T) {
+	client := newChatStreamServer(t,
+		`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call-1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call-2","type":"function","function":{"name":"get_time","arguments":"{}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Austin\"}"}}]},"finish_reason":"tool_calls"}]}`,
+		`[DONE]`,
+	)
+
+	stream, err := client.ChatStream(ChatModelId, []wx.ChatMessage{wx.UserMessage("Weather in Austin?")})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	accumulator := wx.NewChatStreamAccumulator()
+	for stream.Next() {
+		accumulator.Add(stream.Current())
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expected no stream error, but got %v", err)
+	}
+
+	message := accumulator.Result().Choices[0].Message
+	if len(message.ToolCalls) != 2 {
+		t.Fatalf("Expected 2 tool calls, but got %d", len(message.ToolCalls))
+	}
+
+	first, second := message.ToolCalls[0], message.ToolCalls[1]
+	if first.ID != "call-1" || first.Function.Name != "get_weather" || first.Function.Arguments != `{"city":"Austin"}` {
+		t.Fatalf("Expected get_weather call with merged arguments, but got %+v", first)
+	}
+	if second.ID != "call-2" || second.Function.Name != "get_time" {
+		t.Fatalf("Expected get_time call, but got %+v", second)
+	}
+}
+
+func TestChatStreamDroppedConnection(t *testing.T) {
+	client := newChatStreamServer(t,
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":"I am"}}]}`,
+	)
+
+	stream, err := client.ChatStream(ChatModelId, []wx.ChatMessage{wx.UserMessage("Who are you?")})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+
+	if !errors.Is(stream.Err(), io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected io.ErrUnexpectedEOF, but got %v", stream.Err())
+	}
+}
+
+func TestChatStreamMalformedEvent(t *testing.T) {
+	client := newChatStreamServer(t,
+		`{"choices":[{"index":0,"delta":{"content":"Hi"}}]}`,
+		`{not json`,
+	)
+
+	stream, err := client.ChatStream(ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	chunks := 0
+	for stream.Next() {
+		chunks++
+	}
+
+	if chunks != 1 {
+		t.Fatalf("Expected 1 chunk before the malformed event, but got %d", chunks)
+	}
+	if stream.Err() == nil {
+		t.Fatal("Expected a stream error for the malformed event, but got nil")
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const (
+	ChatModelId = "meta-llama/llama-3-1-8b-instruct"
+)
+
+func TestChat(t *testing.T) {
+	var payload map[string]any
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wx.ChatEndpoint {
+			t.Errorf("Expected path %s, but got %s", wx.ChatEndpoint, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer "+TestAccessToken {
+			t.Errorf("Expected bearer token, but got %s", auth)
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		w.Write([]byte(`{
+			"id": "chat-1",
+			"model_id": "meta-llama/llama-3-1-8b-instruct",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "I am an AI assistant."}, "finish_reason": "stop"}],
+			"created": 1720000000,
+			"usage": {"prompt_tokens": 20, "completion_tokens": 6, "total_tokens": 26}
+		}`))
+	})
+	client := getTestClient(t, server)
+
+	result, err := client.Chat(
+		ChatModelId,
+		[]wx.ChatMessage{
+			wx.SystemMessage("You are a helpful assistant."),
+			wx.UserMessageParts(wx.TextPart("Who are you?")),
+		},
+		wx.WithChatTemperature(0.4),
+		wx.WithChatMaxTokens(128),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if payload["project_id"] != TestProjectID || payload["model_id"] != ChatModelId {
+		t.Fatalf("Expected project and model IDs in payload, but got %v", payload)
+	}
+	if payload["temperature"] != 0.4 || payload["max_tokens"] != float64(128) {
+		t.Fatalf("Expected options to be inlined in payload, but got %v", payload)
+	}
+
+	messages := payload["messages"].([]any)
+	if content := messages[0].(map[string]any)["content"]; content != "You are a helpful assistant." {
+		t.Fatalf("Expected system content to be a string, but got %v", content)
+	}
+	if parts, ok := messages[1].(map[string]any)["content"].([]any); !ok || len(parts) != 1 {
+		t.Fatalf("Expected user content to be a list of parts, but got %v", messages[1])
+	}
+
+	if len(result.Choices) != 1 {
+		t.Fatalf("Expected 1 choice, but got %d", len(result.Choices))
+	}
+
+	choice := result.Choices[0]
+	if choice.Message.Role != wx.AssistantRole || choice.Message.Content != "I am an AI assistant." {
+		t.Fatalf("Expected assistant message, but got %+v", choice.Message)
+	}
+	if choice.FinishReason != wx.FinishStop {
+		t.Fatalf("Expected finish reason %s, but got %s", wx.FinishStop, choice.FinishReason)
+	}
+	if result.Usage.TotalTokens != 26 {
+		t.Fatalf("Expected 26 total tokens, but got %d", result.Usage.TotalTokens)
+	}
+}
+
+func TestChatEmptyMessagesError(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to reach the server")
+	})
+	client := getTestClient(t, server)
+
+	_, err := client.Chat(ChatModelId, nil)
+	if err == nil {
+		t.Fatal("Expected error for empty messages, but got nil")
+	}
+}
+
+func TestChatMessageJSON(t *testing.T) {
+	message := wx.ChatMessage{
+		Role: wx.AssistantRole,
+		ToolCalls: []wx.ToolCall{{
+			ID:       "call-1",
+			Type:     "function",
+			Function: wx.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Austin"}`},
+		}},
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	var decoded wx.ChatMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if decoded.Role != wx.AssistantRole || decoded.Content != "" || len(decoded.ToolCalls) != 1 {
+		t.Fatalf("Expected message to round trip, but got %+v", decoded)
+	}
+	if decoded.ToolCalls[0].Function.Arguments != `{"city":"Austin"}` {
+		t.Fatalf("Expected tool call arguments to round trip, but got %s", decoded.ToolCalls[0].Function.Arguments)
+	}
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// fakeDoer answers IAM and watsonx requests in memory
+type fakeDoer struct {
+	requests []*http.Request
+}
+
+func (d *fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	d.requests = append(d.requests, req)
+
+	body, _ := json.Marshal(wx.TokenResponse{
+		AccessToken: TestAccessToken,
+		Expiration:  time.Now().Add(time.Hour).Unix(),
+	})
+	if req.URL.Path != wx.TokenPath {
+		body = []byte(`{"results":[{"generated_text":"Hello from a fake","stop_reason":"eos_token"}]}`)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (d *fakeDoer) DoWithRetry(req *http.Request) (*http.Response, error) {
+	return d.Do(req)
+}
+
+func TestClientWithDoer(t *testing.T) {
+	doer := &fakeDoer{}
+
+	client, err := wx.NewClient(
+		wx.WithWatsonxAPIKey("test-api-key"),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithDoer(doer),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	result, err := client.GenerateText("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if result.Text != "Hello from a fake" {
+		t.Fatalf("Expected text from the fake, but got %s", result.Text)
+	}
+	if len(doer.requests) != 2 {
+		t.Fatalf("Expected IAM and generation requests to go through the doer, but got %d requests", len(doer.requests))
+	}
+	if doer.requests[0].URL.Host != wx.IAMCloudHost {
+		t.Fatalf("Expected first request to go to IAM, but got %s", doer.requests[0].URL)
+	}
+}
+
+func TestClientWithHTTPClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(wx.TokenPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wx.TokenResponse{
+			AccessToken: TestAccessToken,
+			Expiration:  time.Now().Add(time.Hour).Unix(),
+		})
+	})
+	mux.HandleFunc(wx.GenerateTextEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"generated_text":"Hello over TLS","stop_reason":"eos_token"}]}`))
+	})
+
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	// the test server's client trusts its self-signed certificate
+	host := server.Listener.Addr().String()
+	client, err := wx.NewClient(
+		wx.WithURL(host),
+		wx.WithIAM(host),
+		wx.WithWatsonxAPIKey("test-api-key"),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	result, err := client.GenerateText("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if result.Text != "Hello over TLS" {
+		t.Fatalf("Expected text from the TLS server, but got %s", result.Text)
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateTextContextCancelled(t *testing.T) {
+	requests := 0
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"results":[{"generated_text":"hello"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GenerateTextContext(ctx, "dumby model", "Hi, who are you?")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, but got %v", err)
+	}
+
+	if requests != 0 {
+		t.Fatalf("Expected no request to reach the server, but got %d", requests)
+	}
+}
+
+func TestEmbedDocumentsContextDeadline(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	})
+	client := getTestClient(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.EmbedDocumentsContext(ctx, EmbeddingModelId, []string{"Hello, world!"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, but got %v", err)
+	}
+}
+
+func TestGenerateTextStreamContextCancelClosesStream(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\nevent: message\ndata: {\"results\":[{\"generated_text\":\"Hello\"}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	client := getTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dataChan, err := client.GenerateTextStreamContext(ctx, "dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	first := <-dataChan
+	if first.Text != "Hello" {
+		t.Fatalf("Expected first chunk to be Hello, but got %s", first.Text)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-dataChan:
+		if ok {
+			t.Fatal("Expected stream to be closed after cancellation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected stream to close promptly after cancellation")
+	}
+}
+
+func TestRetryWithContextStopsRetrying(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	_, err := wx.Retry(
+		func() (*http.Response, error) {
+			attempts++
+			cancel()
+			return nil, errors.New("connection refused")
+		},
+		wx.WithContext(ctx),
+	)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, but got %v", err)
+	}
+
+	if attempts != 1 {
+		t.Fatalf("Expected 1 attempt, but got %d", attempts)
+	}
+}
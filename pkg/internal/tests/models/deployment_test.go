@@ -0,0 +1,144 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const (
+	TestDeploymentID = "test-deployment-id"
+)
+
+func TestGenerateTextDeployment(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := wx.DeploymentsEndpoint + "/" + TestDeploymentID + "/text/generation"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, but got %s", expectedPath, r.URL.Path)
+		}
+
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Expected a JSON payload, but got an error: %v", err)
+		}
+		if _, ok := payload["model_id"]; ok {
+			t.Errorf("Expected no model_id in a deployment payload, but got %v", payload["model_id"])
+		}
+		if _, ok := payload["project_id"]; ok {
+			t.Errorf("Expected no project_id in a deployment payload, but got %v", payload["project_id"])
+		}
+		if _, ok := payload["input"]; ok {
+			t.Errorf("Expected no input for a prompt template deployment, but got %v", payload["input"])
+		}
+
+		parameters, _ := payload["parameters"].(map[string]any)
+		variables, _ := parameters["prompt_variables"].(map[string]any)
+		if variables["name"] != "Ada" {
+			t.Errorf("Expected prompt variable name to be Ada, but got %v", variables["name"])
+		}
+		if parameters["max_new_tokens"] != 10.0 {
+			t.Errorf("Expected max_new_tokens to be 10, but got %v", parameters["max_new_tokens"])
+		}
+
+		w.Write([]byte(`{"results": [{"generated_text": "Hello, Ada", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	result, err := client.GenerateTextDeployment(TestDeploymentID, "",
+		wx.WithPromptVariables(map[string]string{"name": "Ada"}),
+		wx.WithMaxNewTokens(10),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if result.Text != "Hello, Ada" {
+		t.Fatalf("Expected generated text to be 'Hello, Ada', but got %s", result.Text)
+	}
+}
+
+func TestGenerateTextDeploymentStream(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := wx.DeploymentsEndpoint + "/" + TestDeploymentID + "/text/generation_stream"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, but got %s", expectedPath, r.URL.Path)
+		}
+
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["input"] != "Hi, who are you?" {
+			t.Errorf("Expected input to be the prompt, but got %v", payload["input"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\nevent: message\ndata: {\"results\":[{\"generated_text\":\"I am\",\"stop_reason\":\"not_finished\"}]}\n\n")
+		fmt.Fprint(w, "id: 2\nevent: message\ndata: {\"results\":[{\"generated_text\":\" tuned.\",\"stop_reason\":\"eos_token\"}]}\n\n")
+	})
+	client := getTestClient(t, server)
+
+	dataChan, err := client.GenerateTextDeploymentStream(TestDeploymentID, "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	generatedText := ""
+	for data := range dataChan {
+		generatedText += data.Text
+	}
+
+	if generatedText != "I am tuned." {
+		t.Fatalf("Expected generated text to be 'I am tuned.', but got %s", generatedText)
+	}
+}
+
+func TestGenerateTextDeploymentValidation(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	})
+	client := getTestClient(t, server)
+
+	if _, err := client.GenerateTextDeployment("", "Hi, who are you?"); err == nil {
+		t.Fatal("Expected an error for an empty deployment ID, but got nil")
+	}
+	if _, err := client.GenerateTextDeployment(TestDeploymentID, ""); err == nil {
+		t.Fatal("Expected an error for an empty prompt without prompt variables, but got nil")
+	}
+	if _, err := client.GenerateTextDeployment("..", "Hi, who are you?"); err == nil {
+		t.Fatal("Expected an error for a .. deployment ID, but got nil")
+	}
+}
+
+func TestGenerateTextDeploymentEscapesID(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := wx.DeploymentsEndpoint + "/serving%2Fname/text/generation"
+		if r.URL.EscapedPath() != expectedPath {
+			t.Errorf("Expected path %s, but got %s", expectedPath, r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	if _, err := client.GenerateTextDeployment("serving/name", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+}
+
+func TestPromptVariablesOnlySentToDeployments(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		parameters, _ := payload["parameters"].(map[string]any)
+		if _, ok := parameters["prompt_variables"]; ok {
+			t.Errorf("Expected no prompt_variables outside of deployments, but got %v", parameters["prompt_variables"])
+		}
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?", wx.WithPromptVariables(map[string]string{"name": "Ada"})); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+}
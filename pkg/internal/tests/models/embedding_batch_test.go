@@ -0,0 +1,108 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// embeddingBatchHandler embeds each text "text-<n>" as [n] and fails batches containing "fail"
+func embeddingBatchHandler(requests, inFlight, maxInFlight *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var payload wx.EmbeddingPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		results := make([]string, len(payload.Inputs))
+		for i, input := range payload.Inputs {
+			if input == "fail" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors": [{"code": "invalid_input", "message": "bad input"}]}`))
+				return
+			}
+			results[i] = fmt.Sprintf(`{"embedding": [%s]}`, strings.TrimPrefix(input, "text-"))
+		}
+
+		fmt.Fprintf(w, `{"model_id": %q, "results": [%s], "input_token_count": %d}`,
+			payload.Model, strings.Join(results, ","), len(payload.Inputs))
+	}
+}
+
+func TestEmbedDocumentsBatched(t *testing.T) {
+	var requests, inFlight, maxInFlight atomic.Int32
+	server := newTestServer(t, embeddingBatchHandler(&requests, &inFlight, &maxInFlight))
+	client := getTestClient(t, server)
+
+	texts := make([]string, 25)
+	for i := range texts {
+		texts[i] = "text-" + strconv.Itoa(i)
+	}
+
+	response, err := client.EmbedDocumentsBatched(EmbeddingModelId, texts,
+		wx.WithEmbeddingBatchSize(4),
+		wx.WithEmbeddingConcurrency(2),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if requests.Load() != 7 {
+		t.Fatalf("Expected 7 requests, but got %d", requests.Load())
+	}
+	if maxInFlight.Load() > 2 {
+		t.Fatalf("Expected at most 2 concurrent requests, but got %d", maxInFlight.Load())
+	}
+	if response.InputTokenCount != 25 {
+		t.Fatalf("Expected 25 input tokens, but got %d", response.InputTokenCount)
+	}
+	if len(response.Results) != 25 {
+		t.Fatalf("Expected 25 results, but got %d", len(response.Results))
+	}
+	for i, result := range response.Results {
+		if len(result.Embedding) != 1 || result.Embedding[0] != float64(i) {
+			t.Fatalf("Expected embedding [%d] at index %d, but got %v", i, i, result.Embedding)
+		}
+	}
+}
+
+func TestEmbedDocumentsBatchedPartialFailure(t *testing.T) {
+	var requests, inFlight, maxInFlight atomic.Int32
+	server := newTestServer(t, embeddingBatchHandler(&requests, &inFlight, &maxInFlight))
+	client := getTestClient(t, server)
+
+	texts := []string{"text-0", "text-1", "text-2", "fail", "text-4", "text-5"}
+
+	response, err := client.EmbedDocumentsBatched(EmbeddingModelId, texts, wx.WithEmbeddingBatchSize(2))
+
+	batchErrs := wx.BatchErrors(err)
+	if len(batchErrs) != 1 || batchErrs[0].Index != 1 {
+		t.Fatalf("Expected batch 1 to fail, but got %v", err)
+	}
+
+	for i, result := range response.Results {
+		failed := i == 2 || i == 3
+		if failed != (result.Embedding == nil) {
+			t.Fatalf("Expected only texts 2 and 3 to have no embedding, but text %d has %v", i, result.Embedding)
+		}
+	}
+	if response.InputTokenCount != 4 {
+		t.Fatalf("Expected 4 input tokens from the successful batches, but got %d", response.InputTokenCount)
+	}
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// newEmbeddingCacheServer embeds each text as its length and records the inputs of every request
+func newEmbeddingCacheServer(t *testing.T, cache wx.EmbeddingCache) (*wx.Client, *[][]string) {
+	var requests [][]string
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload wx.EmbeddingPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		requests = append(requests, payload.Inputs)
+
+		results := make([]string, len(payload.Inputs))
+		for i, input := range payload.Inputs {
+			results[i] = fmt.Sprintf(`{"embedding": [%d, 1]}`, len(input))
+		}
+
+		fmt.Fprintf(w, `{"model_id": %q, "results": [%s], "input_token_count": %d}`,
+			payload.Model, strings.Join(results, ","), len(payload.Inputs))
+	})
+
+	return getTestClient(t, server, wx.WithEmbeddingCache(cache)), &requests
+}
+
+func TestEmbeddingCache(t *testing.T) {
+	cache := wx.NewLRUEmbeddingCache(10)
+	client, requests := newEmbeddingCacheServer(t, cache)
+
+	if _, err := client.EmbedDocuments(EmbeddingModelId, []string{"a", "bb"}); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	response, err := client.EmbedDocuments(EmbeddingModelId, []string{"ccc", "a", "ccc", "bb"}, wx.WithEmbeddingReturnOptions(true))
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if len(*requests) != 2 || len((*requests)[1]) != 1 || (*requests)[1][0] != "ccc" {
+		t.Fatalf("Expected only the missing text ccc to be sent once, but got requests %v", *requests)
+	}
+	for i, expected := range []float64{3, 1, 3, 2} {
+		if response.Results[i].Embedding[0] != expected {
+			t.Fatalf("Expected embedding [%v 1] at index %d, but got %v", expected, i, response.Results[i].Embedding)
+		}
+	}
+	if response.Results[1].Input != "a" {
+		t.Fatalf("Expected the input text of a cached result, but got %q", response.Results[1].Input)
+	}
+	if response.InputTokenCount != 1 {
+		t.Fatalf("Expected only the sent text to count as input tokens, but got %d", response.InputTokenCount)
+	}
+
+	// modifying a result must not modify the cache
+	wx.Normalize(response.Results[1].Embedding)
+	response, _ = client.EmbedQuery(EmbeddingModelId, "a", wx.WithEmbeddingFloat32())
+	if len(*requests) != 2 || response.Results[0].Embedding32[0] != 1 {
+		t.Fatalf("Expected the cached float32 embedding [1 1], but got %v", response.Results[0].Embedding32)
+	}
+
+	// a different truncation is a different key
+	client.EmbedQuery(EmbeddingModelId, "a", wx.WithEmbeddingTruncateInputTokens(8))
+	if len(*requests) != 3 {
+		t.Fatalf("Expected a request for another truncation, but got requests %v", *requests)
+	}
+}
+
+func TestEmbeddingCacheRepeatedTextsAreCopied(t *testing.T) {
+	client, _ := newEmbeddingCacheServer(t, wx.NewLRUEmbeddingCache(10))
+
+	response, err := client.EmbedDocuments(EmbeddingModelId, []string{"ccc", "ccc"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	wx.Normalize(response.Results[0].Embedding)
+
+	if response.Results[1].Embedding[0] != 3 {
+		t.Fatalf("Expected the repeated text to keep its own embedding, but got %v", response.Results[1].Embedding)
+	}
+}
+
+func TestLRUEmbeddingCacheEviction(t *testing.T) {
+	cache := wx.NewLRUEmbeddingCache(2)
+	a := wx.NewEmbeddingCacheKey(EmbeddingModelId, 0, "a")
+	b := wx.NewEmbeddingCacheKey(EmbeddingModelId, 0, "b")
+	c := wx.NewEmbeddingCacheKey(EmbeddingModelId, 0, "c")
+
+	cache.Put(a, wx.EmbeddingResult{Embedding: []float64{1}})
+	cache.Put(b, wx.EmbeddingResult{Embedding: []float64{2}})
+	cache.Get(a) // b is now the least recently used
+	cache.Put(c, wx.EmbeddingResult{Embedding: []float64{3}})
+
+	if _, ok := cache.Get(b); ok {
+		t.Fatal("Expected b to be evicted")
+	}
+	if result, ok := cache.Get(a); !ok || result.Embedding[0] != 1 {
+		t.Fatalf("Expected a to be cached, but got %v, %v", result, ok)
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached embeddings, but got %d", cache.Len())
+	}
+}
+
+func TestDiskEmbeddingCache(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := wx.NewDiskEmbeddingCache(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	client, requests := newEmbeddingCacheServer(t, cache)
+
+	if _, err := client.EmbedDocuments(EmbeddingModelId, []string{"a", "bb"}, wx.WithEmbeddingFloat32()); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	// a new cache on the same directory, as in another run
+	cache, _ = wx.NewDiskEmbeddingCache(dir)
+	client, requests = newEmbeddingCacheServer(t, cache)
+
+	response, err := client.EmbedDocuments(EmbeddingModelId, []string{"bb", "a"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if len(*requests) != 0 {
+		t.Fatalf("Expected no request, but got requests %v", *requests)
+	}
+	if response.Results[0].Embedding[0] != 2 || response.Results[1].Embedding[0] != 1 {
+		t.Fatalf("Expected the embeddings from disk in order, but got %v", response.Results)
+	}
+
+	if _, ok := cache.Get(wx.NewEmbeddingCacheKey("other model", 0, "a")); ok {
+		t.Fatal("Expected a miss for another model")
+	}
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateTextAPIError(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{
+			"errors": [{"code": "model_not_supported", "message": "Model 'dumby model' is not supported", "more_info": "https://cloud.ibm.com/apidocs/watsonx-ai"}],
+			"trace": "trace-123",
+			"status_code": 400
+		}`))
+	})
+	client := getTestClient(t, server)
+
+	_, err := client.GenerateText("dumby model", "Hi, who are you?")
+
+	var apiErr *wx.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, but got %v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected status code 400, but got %d", apiErr.StatusCode)
+	}
+	if apiErr.Trace != "trace-123" {
+		t.Fatalf("Expected trace ID trace-123, but got %s", apiErr.Trace)
+	}
+	if !apiErr.HasCode("model_not_supported") || apiErr.Errors[0].MoreInfo == "" {
+		t.Fatalf("Expected error details, but got %+v", apiErr.Errors)
+	}
+	if apiErr.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected response headers, but got %v", apiErr.Header)
+	}
+}
+
+func TestEmbedDocumentsAPIError(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"code":"model_not_found","message":"Model not found"}],"trace":"trace-456"}`))
+	})
+	client := getTestClient(t, server)
+
+	_, err := client.EmbedQuery(EmbeddingModelId, "Hello, world!")
+
+	var apiErr *wx.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !apiErr.HasCode("model_not_found") {
+		t.Fatalf("Expected a 404 APIError, but got %v", err)
+	}
+}
+
+func TestGenerateTokenAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorCode":"BXNIM0415E","errorMessage":"Provided API key could not be found.","context":{"requestId":"request-789"}}`))
+	}))
+	defer server.Close()
+
+	_, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithIAM(server.URL),
+		wx.WithWatsonxAPIKey("bad-api-key"),
+		wx.WithWatsonxProjectID(TestProjectID),
+	)
+
+	var apiErr *wx.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, but got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || !apiErr.HasCode("BXNIM0415E") || apiErr.Trace != "request-789" {
+		t.Fatalf("Expected IAM error details, but got %+v", apiErr)
+	}
+}
+
+func TestChatStreamErrorEvent(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message\ndata: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"))
+		w.Write([]byte("event: error\ndata: {\"errors\":[{\"code\":\"internal_error\",\"message\":\"Model crashed\"}],\"trace\":\"trace-1\",\"status_code\":500}\n\n"))
+	})
+	client := getTestClient(t, server)
+
+	stream, err := client.ChatStream(ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+
+	var apiErr *wx.APIError
+	if !errors.As(stream.Err(), &apiErr) || apiErr.StatusCode != http.StatusInternalServerError || !apiErr.HasCode("internal_error") {
+		t.Fatalf("Expected a 500 APIError from the stream, but got %v", stream.Err())
+	}
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const foundationModelSpec = `{
+	"model_id": %q,
+	"label": "granite-13b-chat-v2",
+	"provider": "IBM",
+	"functions": [{"id": "text_generation"}, {"id": "text_chat"}],
+	"task_ids": ["question_answering", "summarization"],
+	"model_limits": {"max_sequence_length": 8192, "max_output_tokens": 4096},
+	"lifecycle": [
+		{"id": "available", "start_date": "2023-11-30"},
+		{"id": "deprecated", "start_date": "2024-08-01", "alternative_model_ids": ["ibm/granite-13b-chat-v3"]},
+		{"id": "withdrawn", "start_date": %q}
+	],
+	"tech_preview": false
+}`
+
+func TestListFoundationModels(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected method GET, but got %s", r.Method)
+		}
+		if r.URL.Path != wx.FoundationModelSpecsEndpoint {
+			t.Errorf("Expected path %s, but got %s", wx.FoundationModelSpecsEndpoint, r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("filters") != "function_text_chat,task_summarization" {
+			t.Errorf("Expected filters function_text_chat,task_summarization, but got %s", query.Get("filters"))
+		}
+		if query.Get("tech_preview") != "true" || query.Get("limit") != "1" || query.Get("version") == "" {
+			t.Errorf("Expected tech_preview, limit and version query parameters, but got %s", r.URL.RawQuery)
+		}
+
+		fmt.Fprintf(w, `{
+			"total_count": 2,
+			"limit": 1,
+			"next": {"href": "https://us-south.ml.cloud.ibm.com/ml/v1/foundation_model_specs?limit=1&start=abc"},
+			"resources": [`+foundationModelSpec+`]
+		}`, "ibm/granite-13b-chat-v2", "2099-01-01")
+	})
+	client := getTestClient(t, server)
+
+	list, err := client.ListFoundationModels(
+		wx.WithFoundationModelFunction(wx.ModelFunctionTextChat),
+		wx.WithFoundationModelTask("summarization"),
+		wx.WithFoundationModelTechPreview(true),
+		wx.WithFoundationModelLimit(1),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if list.TotalCount != 2 || len(list.Resources) != 1 {
+		t.Fatalf("Expected a page of 1 out of 2 models, but got %+v", list)
+	}
+	if list.NextStart() != "abc" {
+		t.Fatalf("Expected next start abc, but got %s", list.NextStart())
+	}
+
+	spec := list.Resources[0]
+	if spec.ModelLimits.MaxSequenceLength != 8192 {
+		t.Fatalf("Expected max sequence length 8192, but got %d", spec.ModelLimits.MaxSequenceLength)
+	}
+	if !spec.HasFunction(wx.ModelFunctionTextChat) || spec.HasFunction(wx.ModelFunctionEmbedding) {
+		t.Fatalf("Expected text_chat but not embedding functions, but got %v", spec.Functions)
+	}
+
+	deprecation, ok := spec.Deprecation()
+	if !ok || deprecation.StartDate.Format("2006-01-02") != "2024-08-01" {
+		t.Fatalf("Expected deprecation on 2024-08-01, but got %v", deprecation)
+	}
+	if deprecation.AlternativeModelIDs[0] != "ibm/granite-13b-chat-v3" {
+		t.Fatalf("Expected alternative model ibm/granite-13b-chat-v3, but got %v", deprecation.AlternativeModelIDs)
+	}
+}
+
+func TestListAllFoundationModels(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("start") {
+		case "":
+			fmt.Fprintf(w, `{"total_count": 2, "next": {"href": "/ml/v1/foundation_model_specs?start=page2"}, "resources": [`+foundationModelSpec+`]}`, "model-1", "2099-01-01")
+		case "page2":
+			fmt.Fprintf(w, `{"total_count": 2, "resources": [`+foundationModelSpec+`]}`, "model-2", "2099-01-01")
+		default:
+			t.Errorf("Unexpected start %s", r.URL.Query().Get("start"))
+		}
+	})
+	client := getTestClient(t, server)
+
+	specs, err := client.ListAllFoundationModels()
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if len(specs) != 2 || specs[0].ModelID != "model-1" || specs[1].ModelID != "model-2" {
+		t.Fatalf("Expected models model-1 and model-2, but got %v", specs)
+	}
+}
+
+func TestValidateModelID(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("filters") {
+		case "modelid_ibm/available":
+			fmt.Fprintf(w, `{"resources": [`+foundationModelSpec+`]}`, "ibm/available", "2099-01-01")
+		case "modelid_ibm/withdrawn":
+			fmt.Fprintf(w, `{"resources": [`+foundationModelSpec+`]}`, "ibm/withdrawn", "2024-11-30")
+		default:
+			w.Write([]byte(`{"resources": []}`))
+		}
+	})
+	client := getTestClient(t, server)
+
+	spec, err := client.ValidateModelID("ibm/available")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if stage, _ := spec.LifecycleAt(spec.Lifecycle[1].StartDate.Time); stage.ID != wx.ModelDeprecated {
+		t.Fatalf("Expected the model to be deprecated, but got %s", stage.ID)
+	}
+
+	if _, err := client.ValidateModelID("ibm/withdrawn"); !errors.Is(err, wx.ErrModelWithdrawn) {
+		t.Fatalf("Expected ErrModelWithdrawn, but got %v", err)
+	}
+	if _, err := client.ValidateModelID("ibm/unknown"); !errors.Is(err, wx.ErrModelNotFound) {
+		t.Fatalf("Expected ErrModelNotFound, but got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestGenerateTextBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+
+		var payload wx.GenerateTextPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		if payload.Prompt == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"code": "invalid_input", "message": "bad prompt"}]}`))
+			return
+		}
+
+		fmt.Fprintf(w, `{"results": [{"generated_text": "echo %s", "stop_reason": "eos_token"}]}`, payload.Prompt)
+	})
+	client := getTestClient(t, server)
+
+	prompts := make([]string, 20)
+	for i := range prompts {
+		prompts[i] = fmt.Sprint(i)
+	}
+	prompts[7] = "fail"
+
+	var progress []int
+	results, err := client.GenerateTextBatch("dumby model", prompts,
+		wx.WithMaxNewTokens(5),
+		wx.WithBatchConcurrency(3),
+		wx.WithBatchProgress(func(completed, total int) {
+			if total != len(prompts) {
+				t.Errorf("Expected a total of %d, but got %d", len(prompts), total)
+			}
+			progress = append(progress, completed)
+		}),
+	)
+
+	for i, result := range results {
+		expected := "echo " + prompts[i]
+		if i == 7 {
+			expected = ""
+		}
+		if result.Text != expected {
+			t.Fatalf("Expected result %d to be %q, but got %q", i, expected, result.Text)
+		}
+	}
+
+	batchErrs := wx.BatchErrors(err)
+	if len(batchErrs) != 1 || batchErrs[0].Index != 7 {
+		t.Fatalf("Expected prompt 7 to fail, but got %v", err)
+	}
+	var apiErr *wx.APIError
+	if !errors.As(batchErrs[0], &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected an APIError with status 400, but got %v", batchErrs[0].Err)
+	}
+
+	if maxInFlight.Load() > 3 {
+		t.Fatalf("Expected at most 3 concurrent requests, but got %d", maxInFlight.Load())
+	}
+	if len(progress) != len(prompts) || progress[len(progress)-1] != len(prompts) {
+		t.Fatalf("Expected progress up to %d, but got %v", len(prompts), progress)
+	}
+}
+
+func TestGenerateTextBatchCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests atomic.Int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if requests.Add(1) == 2 {
+			cancel()
+		}
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	prompts := []string{"a", "b", "c", "d", "e"}
+	results, err := client.GenerateTextBatchContext(ctx, "dumby model", prompts, wx.WithBatchConcurrency(1))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, but got %v", err)
+	}
+	if results[0].Text != "Hello" {
+		t.Fatalf("Expected the first prompt to complete, but got %+v", results[0])
+	}
+	if requests.Load() > 2 {
+		t.Fatalf("Expected no request after cancellation, but got %d requests", requests.Load())
+	}
+
+	failed := wx.BatchErrors(err)
+	if failed[len(failed)-1].Index != len(prompts)-1 {
+		t.Fatalf("Expected the last prompt to fail, but got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// logBuffer collects the JSON records of a debug logger
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *logBuffer) records(t *testing.T) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode log record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func newDebugLogger() (*slog.Logger, *logBuffer) {
+	buf := &logBuffer{}
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), buf
+}
+
+func TestLoggerRequestsAndRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"errors": [{"code": "unavailable", "message": "try again"}]}`)
+			return
+		}
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+
+	logger, buf := newDebugLogger()
+	client := getTestClient(t, server, wx.WithLogger(logger), wx.WithRetryPolicy(wx.WithBackoff(0), wx.WithMaxJitter(0)))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	var request, retry, response map[string]any
+	iamLogged := false
+	for _, record := range buf.records(t) {
+		if record["level"] != "DEBUG" {
+			t.Errorf("Expected debug records only, got %v", record)
+		}
+		switch {
+		case record["msg"] == "watsonx request":
+			request = record
+		case record["msg"] == "watsonx retry":
+			retry = record
+		case record["msg"] == "watsonx response" && record["endpoint"] == wx.TokenPath:
+			iamLogged = true
+		case record["msg"] == "watsonx response":
+			response = record
+		}
+	}
+
+	if request == nil || request["method"] != http.MethodPost || request["endpoint"] != wx.GenerateTextEndpoint {
+		t.Errorf("Expected the request to be logged, got %v", request)
+	}
+	if body, _ := request["body"].(string); !strings.Contains(body, "Hi, who are you?") {
+		t.Errorf("Expected the payload to be logged, got %q", body)
+	}
+	if retry == nil || retry["attempt"] != float64(1) || retry["endpoint"] != wx.GenerateTextEndpoint {
+		t.Errorf("Expected the retry to be logged, got %v", retry)
+	}
+	if response == nil || response["status"] != float64(http.StatusOK) || response["duration"] == nil {
+		t.Errorf("Expected the response to be logged, got %v", response)
+	}
+	if !iamLogged {
+		t.Errorf("Expected the IAM request to be logged")
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"test-api-key", TestAccessToken} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("Expected %q to be redacted from the logs:\n%s", secret, logs)
+		}
+	}
+}
+
+func TestLoggerRedactsSecretsInErrors(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"errors": [{"code": "invalid", "message": "rejected %s and apikey test-api-key"}]}`, r.Header.Get("Authorization"))
+	})
+
+	logger, buf := newDebugLogger()
+	client := getTestClient(t, server, wx.WithLogger(logger))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "rejected") {
+		t.Fatalf("Expected the error to be logged:\n%s", logs)
+	}
+	for _, secret := range []string{"test-api-key", TestAccessToken} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("Expected %q to be redacted from the logs:\n%s", secret, logs)
+		}
+	}
+}
+
+func TestLoggerRedactedPrompts(t *testing.T) {
+	mux := newUsageServer(t)
+	mux.HandleFunc(wx.DeploymentsEndpoint+"/"+TestDeploymentID+"/text/generation", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	server := newTestServer(t, mux.ServeHTTP)
+
+	logger, buf := newDebugLogger()
+	client := getTestClient(t, server, wx.WithLogger(logger), wx.WithRedactedPrompts())
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.GenerateTextDeployment(TestDeploymentID, "", wx.WithPromptVariables(map[string]string{"name": "secret name"})); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.Chat(ChatModelId, []wx.ChatMessage{wx.UserMessage("my private question")}); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	logs := buf.String()
+	for _, prompt := range []string{"Hi, who are you?", "secret name", "my private question"} {
+		if strings.Contains(logs, prompt) {
+			t.Errorf("Expected %q to be redacted from the logs:\n%s", prompt, logs)
+		}
+	}
+	if !strings.Contains(logs, "dumby model") {
+		t.Errorf("Expected the rest of the payload to be logged:\n%s", logs)
+	}
+}
+
+func TestLoggerSilentByDefault(t *testing.T) {
+	logger, buf := newDebugLogger()
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	server := newTestServer(t, newUsageServer(t).ServeHTTP)
+	client := getTestClient(t, server)
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if logs := buf.String(); logs != "" {
+		t.Errorf("Expected no logs by default, got:\n%s", logs)
+	}
+}
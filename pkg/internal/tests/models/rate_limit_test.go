@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestRateLimitMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server, wx.WithRateLimit(wx.WithMaxConcurrentRequests(2)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+				t.Errorf("Expected no error, but got an error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight.Load() != 2 {
+		t.Fatalf("Expected 2 concurrent requests at most, but got %d", maxInFlight.Load())
+	}
+}
+
+func TestRateLimitStreamHoldsSlot(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\nevent: message\ndata: {\"results\":[{\"generated_text\":\"Hello\",\"stop_reason\":\"eos_token\"}]}\n\n")
+	})
+	client := getTestClient(t, server, wx.WithRateLimit(wx.WithMaxConcurrentRequests(1)))
+
+	stream, err := client.OpenTextStream("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.OpenTextStreamContext(ctx, "dumby model", "Hi, who are you?"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the open stream to hold the only slot, but got %v", err)
+	}
+
+	stream.Close()
+
+	stream, err = client.OpenTextStream("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected the slot to be released on close, but got an error: %v", err)
+	}
+	stream.Close()
+}
+
+func TestRateLimitRequestsPerSecond(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server, wx.WithRateLimit(wx.WithRequestsPerSecond(20, 1)))
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+			t.Fatalf("Expected no error, but got an error: %v", err)
+		}
+	}
+
+	// the first call goes out at once, the next 4 are spaced 50ms apart
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Fatalf("Expected 5 calls at 20 per second to take 200ms, but took %v", elapsed)
+	}
+}
+
+func TestRateLimitRequestsPerSecondCountsRetries(t *testing.T) {
+	var requests atomic.Int32
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"errors": [{"code": "unavailable", "message": "try again"}]}`)
+			return
+		}
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server,
+		wx.WithRateLimit(wx.WithRequestsPerSecond(0.01, 2)),
+		wx.WithRetryPolicy(wx.WithBackoff(0), wx.WithMaxJitter(0)),
+	)
+
+	// the retry takes the second request of the burst
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("Expected 2 requests, but got %d", requests.Load())
+	}
+
+	// so the next call waits about 100s for the bucket to refill
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.GenerateTextContext(ctx, "dumby model", "Hi, who are you?"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the call to wait for the requests per second, but got %v", err)
+	}
+}
+
+func TestRateLimitTokensPerMinute(t *testing.T) {
+	var requests atomic.Int32
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		requests.Add(1)
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "input_token_count": 40, "generated_token_count": 60, "stop_reason": "eos_token"}]}`))
+	})
+	client := getTestClient(t, server, wx.WithRateLimit(wx.WithTokensPerMinute(60)))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	// 100 tokens out of a budget of 60 per minute: the next call waits about 40s
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.GenerateTextContext(ctx, "dumby model", "Hi, who are you?"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the call to wait for the token budget, but got %v", err)
+	}
+
+	if requests.Load() != 1 {
+		t.Fatalf("Expected 1 request, but got %d", requests.Load())
+	}
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// recordingServer fails the first failures requests with a 503 and records every body it receives
+type recordingServer struct {
+	mu       sync.Mutex
+	bodies   []string
+	failures int
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bodies = append(s.bodies, string(body))
+	if len(s.bodies) <= s.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte(`{"results":[{"generated_text":"Hello","stop_reason":"eos_token"}]}`))
+}
+
+func TestRetriedRequestsReplayBody(t *testing.T) {
+	recorder := &recordingServer{failures: 2}
+	server := newTestServer(t, recorder.ServeHTTP)
+	client := getTestClient(t, server, wx.WithRetryPolicy(wx.WithBackoff(0), wx.WithMaxJitter(0)))
+
+	_, err := client.GenerateText("dumby model", "Hi, who are you?", wx.WithMaxNewTokens(10))
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if len(recorder.bodies) != 3 {
+		t.Fatalf("Expected 3 attempts, but got %d", len(recorder.bodies))
+	}
+	if !strings.Contains(recorder.bodies[0], "Hi, who are you?") {
+		t.Fatalf("Expected first attempt to carry the prompt, but got %s", recorder.bodies[0])
+	}
+	for i, body := range recorder.bodies[1:] {
+		if body != recorder.bodies[0] {
+			t.Fatalf("Expected retry %d to send %s, but got %s", i+1, recorder.bodies[0], body)
+		}
+	}
+}
+
+func TestDoWithRetryReplaysUnbufferedBody(t *testing.T) {
+	recorder := &recordingServer{failures: 1}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// an io.Reader without GetBody, so the client must buffer it to replay it
+	payload := `{"input":"Hi, who are you?"}`
+	req, err := http.NewRequest(http.MethodPost, server.URL, io.MultiReader(strings.NewReader(payload)))
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	req = req.WithContext(wx.ContextWithRetryPolicy(context.Background(), wx.WithBackoff(0), wx.WithMaxJitter(0)))
+
+	res, err := wx.NewHttpClient().DoWithRetry(req)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	res.Body.Close()
+
+	if len(recorder.bodies) != 2 || recorder.bodies[0] != payload || recorder.bodies[1] != payload {
+		t.Fatalf("Expected both attempts to send %s, but got %v", payload, recorder.bodies)
+	}
+}
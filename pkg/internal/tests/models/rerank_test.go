@@ -0,0 +1,81 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const RerankModelId = "cross-encoder/ms-marco-minilm-l-12-v2"
+
+func TestRerank(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wx.RerankEndpoint {
+			t.Errorf("Expected path %s, but got %s", wx.RerankEndpoint, r.URL.Path)
+		}
+
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		if payload["query"] != "What is Go?" {
+			t.Errorf("Expected query to be 'What is Go?', but got %v", payload["query"])
+		}
+		inputs, _ := payload["inputs"].([]any)
+		if len(inputs) != 3 || inputs[1].(map[string]any)["text"] != "Go is a programming language." {
+			t.Errorf("Expected 3 inputs with text fields, but got %v", payload["inputs"])
+		}
+
+		parameters, _ := payload["parameters"].(map[string]any)
+		returnOptions, _ := parameters["return_options"].(map[string]any)
+		if parameters["truncate_input_tokens"] != 512.0 || returnOptions["top_n"] != 2.0 || returnOptions["inputs"] != true || returnOptions["query"] != false {
+			t.Errorf("Expected truncation, top_n and return options in the parameters, but got %v", parameters)
+		}
+
+		w.Write([]byte(`{
+			"model_id": "cross-encoder/ms-marco-minilm-l-12-v2",
+			"results": [
+				{"index": 1, "score": 0.98, "input": {"text": "Go is a programming language."}},
+				{"index": 2, "score": 0.12, "input": {"text": "Go is a board game."}}
+			],
+			"input_token_count": 42
+		}`))
+	})
+	client := getTestClient(t, server)
+
+	documents := []string{"The sky is blue.", "Go is a programming language.", "Go is a board game."}
+	result, err := client.Rerank(RerankModelId, "What is Go?", documents,
+		wx.WithRerankTruncateInputTokens(512),
+		wx.WithRerankTopN(2),
+		wx.WithRerankReturnOptions(true, false),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if len(result.Results) != 2 {
+		t.Fatalf("Expected 2 results, but got %d", len(result.Results))
+	}
+	top := result.Results[0]
+	if top.Index != 1 || top.Score != 0.98 || top.Input == nil || top.Input.Text != documents[1] {
+		t.Fatalf("Expected document 1 to rank first, but got %+v", top)
+	}
+	if result.InputTokenCount != 42 {
+		t.Fatalf("Expected 42 input tokens, but got %d", result.InputTokenCount)
+	}
+}
+
+func TestRerankValidation(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	})
+	client := getTestClient(t, server)
+
+	if _, err := client.Rerank(RerankModelId, "", []string{"document"}); err == nil {
+		t.Fatal("Expected an error for an empty query, but got nil")
+	}
+	if _, err := client.Rerank(RerankModelId, "What is Go?", nil); err == nil {
+		t.Fatal("Expected an error without documents, but got nil")
+	}
+}
@@ -0,0 +1,149 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// fakeTimer records the requested delays and fires immediately
+type fakeTimer struct {
+	delays []time.Duration
+}
+
+func (f *fakeTimer) After(d time.Duration) <-chan time.Time {
+	f.delays = append(f.delays, d)
+	c := make(chan time.Time, 1)
+	c <- time.Now()
+	return c
+}
+
+func failingRequest(t *testing.T, status int, header http.Header) wx.RetryableFuncWithResponse {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return func() (*http.Response, error) {
+		return http.Get(server.URL)
+	}
+}
+
+func TestRetryExponentialBackoff(t *testing.T) {
+	timer := &fakeTimer{}
+
+	wx.Retry(
+		failingRequest(t, http.StatusInternalServerError, nil),
+		wx.WithRetries(6),
+		wx.WithBackoff(100*time.Millisecond),
+		wx.WithMaxJitter(0),
+		wx.WithExponentialBackoff(2),
+		wx.WithMaxBackoff(time.Second),
+		wx.WithTimer(timer),
+	)
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second, // capped
+	}
+	if !reflect.DeepEqual(timer.delays, expected) {
+		t.Fatalf("Expected delays %v, but got %v", expected, timer.delays)
+	}
+}
+
+func TestRetryDecorrelatedJitter(t *testing.T) {
+	timer := &fakeTimer{}
+	base, maxBackoff := 100*time.Millisecond, 2*time.Second
+
+	wx.Retry(
+		failingRequest(t, http.StatusServiceUnavailable, nil),
+		wx.WithRetries(10),
+		wx.WithBackoff(base),
+		wx.WithDecorrelatedJitter(),
+		wx.WithMaxBackoff(maxBackoff),
+		wx.WithTimer(timer),
+	)
+
+	// no delay after the last attempt
+	if len(timer.delays) != 9 {
+		t.Fatalf("Expected 9 delays, but got %d", len(timer.delays))
+	}
+
+	previous := base
+	for i, delay := range timer.delays {
+		if delay < base || delay > 3*previous || delay > maxBackoff {
+			t.Fatalf("Expected delay %d to be within [%v, min(%v, %v)], but got %v", i, base, 3*previous, maxBackoff, delay)
+		}
+		previous = delay
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	timer := &fakeTimer{}
+
+	wx.Retry(
+		failingRequest(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}),
+		wx.WithRetries(2),
+		wx.WithBackoff(100*time.Millisecond),
+		wx.WithTimer(timer),
+	)
+
+	expected := []time.Duration{7 * time.Second}
+	if !reflect.DeepEqual(timer.delays, expected) {
+		t.Fatalf("Expected delays %v, but got %v", expected, timer.delays)
+	}
+}
+
+func TestRetryIgnoresRetryAfterWhenDisabled(t *testing.T) {
+	timer := &fakeTimer{}
+
+	wx.Retry(
+		failingRequest(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}),
+		wx.WithRetries(2),
+		wx.WithBackoff(100*time.Millisecond),
+		wx.WithMaxJitter(0),
+		wx.WithRetryAfter(false),
+		wx.WithTimer(timer),
+	)
+
+	expected := []time.Duration{100 * time.Millisecond}
+	if !reflect.DeepEqual(timer.delays, expected) {
+		t.Fatalf("Expected delays %v, but got %v", expected, timer.delays)
+	}
+}
+
+func TestRetryNoWaitAfterLastAttempt(t *testing.T) {
+	timer := &fakeTimer{}
+	var retries []uint
+
+	_, err := wx.Retry(
+		failingRequest(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}}),
+		wx.WithRetries(1),
+		wx.WithTimer(timer),
+		wx.WithOnRetry(func(n uint, err error) { retries = append(retries, n) }),
+	)
+
+	if err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+	if len(timer.delays) != 0 || len(retries) != 0 {
+		t.Fatalf("Expected no wait nor retry after the last attempt, but got delays %v and retries %v", timer.delays, retries)
+	}
+}
+
+func TestExponentialBackoffDoesNotOverflow(t *testing.T) {
+	delay := wx.ExponentialBackoff(2)(200, time.Second, 0)
+	if delay <= 0 {
+		t.Fatalf("Expected a positive delay, but got %v", delay)
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func newStatusServer(t *testing.T, status int, attempts *int32) *wx.Client {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(attempts, 1)
+		w.WriteHeader(status)
+	})
+
+	return getTestClient(t, server, wx.WithRetryPolicy(wx.WithBackoff(0), wx.WithMaxJitter(0)))
+}
+
+func TestDefaultRetryIfDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		var attempts int32
+		client := newStatusServer(t, status, &attempts)
+
+		_, err := client.GenerateText("dumby model", "Hi, who are you?")
+
+		var apiErr *wx.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Fatalf("Expected a %d APIError, but got %v", status, err)
+		}
+		if attempts != 1 {
+			t.Fatalf("Expected 1 attempt for status %d, but got %d", status, attempts)
+		}
+	}
+}
+
+func TestDefaultRetryIfRetriesServerErrors(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		var attempts int32
+		client := newStatusServer(t, status, &attempts)
+
+		_, err := client.GenerateText("dumby model", "Hi, who are you?")
+		if err == nil {
+			t.Fatalf("Expected an error for status %d, but got nil", status)
+		}
+		if attempts != 3 {
+			t.Fatalf("Expected 3 attempts for status %d, but got %d", status, attempts)
+		}
+	}
+}
+
+func TestClientRetryPolicy(t *testing.T) {
+	var attempts int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	client := getTestClient(t, server, wx.WithRetryPolicy(wx.WithRetries(5), wx.WithBackoff(0), wx.WithMaxJitter(0)))
+
+	client.EmbedQuery(EmbeddingModelId, "Hello, world!")
+	if attempts != 5 {
+		t.Fatalf("Expected 5 attempts with the client policy, but got %d", attempts)
+	}
+
+	// the call's policy overrides the client's
+	attempts = 0
+	ctx := wx.ContextWithRetryPolicy(context.Background(), wx.WithRetries(1))
+	client.EmbedQueryContext(ctx, EmbeddingModelId, "Hello, world!")
+	if attempts != 1 {
+		t.Fatalf("Expected 1 attempt with the call policy, but got %d", attempts)
+	}
+}
+
+func TestZeroRetriesMakesOneAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		okHandler(w, r)
+	})
+	client := getTestClient(t, server, wx.WithRetryPolicy(wx.WithRetries(0)))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	ctx := wx.ContextWithRetryPolicy(context.Background(), wx.WithRetries(0))
+	if _, err := client.GenerateTextContext(ctx, "dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if attempts.Load() != 2 {
+		t.Fatalf("Expected 1 attempt per call, but got %d in total", attempts.Load())
+	}
+}
+
+func TestDefaultRetryIf(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errors.New("connection reset by peer"), true},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{&wx.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{&wx.APIError{StatusCode: http.StatusBadGateway}, true},
+		{&wx.APIError{StatusCode: http.StatusBadRequest}, false},
+		{&wx.APIError{StatusCode: http.StatusUnauthorized}, false},
+	}
+
+	for _, c := range cases {
+		if retry := wx.DefaultRetryIf(c.err); retry != c.expected {
+			t.Errorf("Expected DefaultRetryIf(%v) to be %v, but got %v", c.err, c.expected, retry)
+		}
+	}
+}
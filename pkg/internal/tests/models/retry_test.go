@@ -69,7 +69,7 @@ func TestRetryWithNoSuccessStatusOnAnyRequest(t *testing.T) {
 
 	var backoffTime = 2 * time.Second
 	var retryCount uint = 0
-	var expectedRetries uint = 3
+	var expectedRetries uint = 2 // 3 attempts, without waiting after the last one
 
 	sendRequest := func() (*http.Response, error) {
 		return http.Get(server.URL + "/notfound")
@@ -101,7 +101,7 @@ func TestRetryWithNoSuccessStatusOnAnyRequest(t *testing.T) {
 	}
 
 	if retryCount != expectedRetries {
-		t.Errorf("Expected 3 retries, but got %d", retryCount)
+		t.Errorf("Expected %d retries, but got %d", expectedRetries, retryCount)
 	}
 
 	if elapsedTime < expectedMinimumTime {
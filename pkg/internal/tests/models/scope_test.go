@@ -0,0 +1,145 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const (
+	TestSpaceID = "test-space-id"
+)
+
+// payloadRecorder records the project and space IDs of every request and answers for any endpoint
+type payloadRecorder struct {
+	payloads []map[string]any
+}
+
+func (p *payloadRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]any
+	json.NewDecoder(r.Body).Decode(&payload)
+	p.payloads = append(p.payloads, payload)
+
+	w.Write([]byte(`{
+		"results": [{"generated_text": "Hello", "embedding": [0.1, 0.2]}],
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hello"}}]
+	}`))
+}
+
+func (p *payloadRecorder) assertScope(t *testing.T, i int, projectID, spaceID string) {
+	t.Helper()
+
+	payload := p.payloads[i]
+	if _, ok := payload["project_id"]; ok != (projectID != "") || (ok && payload["project_id"] != projectID) {
+		t.Fatalf("Expected project_id %q in request %d, but got %v", projectID, i, payload["project_id"])
+	}
+	if _, ok := payload["space_id"]; ok != (spaceID != "") || (ok && payload["space_id"] != spaceID) {
+		t.Fatalf("Expected space_id %q in request %d, but got %v", spaceID, i, payload["space_id"])
+	}
+}
+
+func TestClientWithSpaceID(t *testing.T) {
+	recorder := &payloadRecorder{}
+	server := newTestServer(t, recorder.ServeHTTP)
+	client, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithIAM(server.URL),
+		wx.WithWatsonxAPIKey("test-api-key"),
+		wx.WithWatsonxSpaceID(TestSpaceID),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client for testing. Error: %v", err)
+	}
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.EmbedQuery(EmbeddingModelId, "Hello, world!"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.Chat(ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")}); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	for i := range recorder.payloads {
+		recorder.assertScope(t, i, "", TestSpaceID)
+	}
+}
+
+func TestPerCallScopeOverride(t *testing.T) {
+	recorder := &payloadRecorder{}
+	server := newTestServer(t, recorder.ServeHTTP)
+	client := getTestClient(t, server)
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?", wx.WithSpaceID("other-space")); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.EmbedQuery(EmbeddingModelId, "Hello, world!", wx.WithEmbeddingProjectID("other-project")); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.Chat(ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")}, wx.WithChatSpaceID("other-space")); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	recorder.assertScope(t, 0, "", "other-space")
+	recorder.assertScope(t, 1, "other-project", "")
+	recorder.assertScope(t, 2, "", "other-space")
+	recorder.assertScope(t, 3, TestProjectID, "")
+
+	_, err := client.GenerateText("dumby model", "Hi, who are you?", wx.WithProjectID("other-project"), wx.WithSpaceID("other-space"))
+	if err == nil {
+		t.Fatal("Expected an error with both a project and a space ID for the call, but got nil")
+	}
+}
+
+func TestScopeValidation(t *testing.T) {
+	t.Setenv(wx.WatsonxProjectIDEnvVarName, "")
+	t.Setenv(wx.WatsonxSpaceIDEnvVarName, "")
+
+	server := newTestServer(t, okHandler)
+
+	_, err := wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithAuthenticator(wx.NewNoAuthAuthenticator()),
+	)
+	if err == nil {
+		t.Fatal("Expected an error without project or space ID, but got nil")
+	}
+
+	t.Setenv(wx.WatsonxProjectIDEnvVarName, TestProjectID)
+	t.Setenv(wx.WatsonxSpaceIDEnvVarName, TestSpaceID)
+
+	_, err = wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithAuthenticator(wx.NewNoAuthAuthenticator()),
+	)
+	if err == nil {
+		t.Fatal("Expected an error with both project and space IDs, but got nil")
+	}
+
+	// an explicit option overrides the other ID from the environment
+	_, err = wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithAuthenticator(wx.NewNoAuthAuthenticator()),
+		wx.WithWatsonxSpaceID(TestSpaceID),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error with an explicit space ID, but got %v", err)
+	}
+
+	// but explicit options do not override each other
+	_, err = wx.NewClient(
+		wx.WithURL(server.URL),
+		wx.WithAuthenticator(wx.NewNoAuthAuthenticator()),
+		wx.WithWatsonxProjectID(TestProjectID),
+		wx.WithWatsonxSpaceID(TestSpaceID),
+	)
+	if err == nil {
+		t.Fatal("Expected an error with both an explicit project and space ID, but got nil")
+	}
+}
@@ -0,0 +1,240 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedClient(t *testing.T, handler http.HandlerFunc, options ...wx.ClientOption) (*wx.Client, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	server := newTestServer(t, handler)
+	options = append([]wx.ClientOption{wx.WithTracerProvider(tracerProvider), wx.WithMeterProvider(meterProvider)}, options...)
+	client := getTestClient(t, server, options...)
+
+	return client, exporter, reader
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("Expected a span named %q, got %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func findMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m
+			}
+		}
+	}
+	t.Fatalf("Expected a metric named %q", name)
+	return metricdata.Metrics{}
+}
+
+func TestTelemetryGenerateSpan(t *testing.T) {
+	client, exporter, reader := newTracedClient(t, newUsageServer(t).ServeHTTP)
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	span := findSpan(t, spans, "text_completion dumby model")
+
+	expected := map[attribute.Key]attribute.Value{
+		"gen_ai.system":                  attribute.StringValue("ibm.watsonx.ai"),
+		"gen_ai.operation.name":          attribute.StringValue("text_completion"),
+		"gen_ai.request.model":           attribute.StringValue("dumby model"),
+		"gen_ai.usage.input_tokens":      attribute.IntValue(5),
+		"gen_ai.usage.output_tokens":     attribute.IntValue(2),
+		"gen_ai.response.finish_reasons": attribute.StringSliceValue([]string{"eos_token"}),
+	}
+	for key, value := range expected {
+		got, ok := spanAttribute(span, key)
+		if !ok || got != value {
+			t.Errorf("Expected attribute %s to be %v, got %v", key, value.Emit(), got.Emit())
+		}
+	}
+	if span.Status.Code == codes.Error {
+		t.Errorf("Expected a successful span, got %v", span.Status)
+	}
+
+	// the IAM request of NewClient is traced too
+	findSpan(t, spans, "watsonx authenticate")
+
+	duration := findMetric(t, reader, "gen_ai.client.operation.duration")
+	if points := duration.Data.(metricdata.Histogram[float64]).DataPoints; len(points) != 1 || points[0].Count != 1 {
+		t.Errorf("Expected one duration measurement, got %+v", points)
+	}
+
+	tokens := findMetric(t, reader, "gen_ai.client.token.usage")
+	sums := map[string]int64{}
+	for _, point := range tokens.Data.(metricdata.Histogram[int64]).DataPoints {
+		tokenType, _ := point.Attributes.Value("gen_ai.token.type")
+		sums[tokenType.AsString()] += point.Sum
+	}
+	if sums["input"] != 5 || sums["output"] != 2 {
+		t.Errorf("Expected 5 input and 2 output tokens, got %v", sums)
+	}
+}
+
+func TestTelemetryStreamTimeToFirstChunk(t *testing.T) {
+	client, exporter, reader := newTracedClient(t, newUsageServer(t).ServeHTTP)
+
+	stream, err := client.OpenTextStream("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	for stream.Next() {
+	}
+	stream.Close()
+
+	span := findSpan(t, exporter.GetSpans(), "text_completion dumby model")
+	if streamed, _ := spanAttribute(span, "watsonx.stream"); !streamed.AsBool() {
+		t.Errorf("Expected the span to be marked as a stream")
+	}
+	if tokens, _ := spanAttribute(span, "gen_ai.usage.output_tokens"); tokens.AsInt64() != 4 {
+		t.Errorf("Expected 4 output tokens, got %v", tokens.Emit())
+	}
+	if len(span.Events) == 0 || span.Events[0].Name != "first_chunk" {
+		t.Errorf("Expected a first_chunk event, got %+v", span.Events)
+	}
+
+	firstChunk := findMetric(t, reader, "gen_ai.client.operation.time_to_first_chunk")
+	if points := firstChunk.Data.(metricdata.Histogram[float64]).DataPoints; len(points) != 1 || points[0].Count != 1 {
+		t.Errorf("Expected one time to first chunk measurement, got %+v", points)
+	}
+}
+
+func TestTelemetryRetryEventsAndErrors(t *testing.T) {
+	var attempts atomic.Int32
+	var onRetryCalls atomic.Int32
+
+	client, exporter, _ := newTracedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"errors": [{"code": "unavailable", "message": "try again"}]}`)
+	}, wx.WithRetryPolicy(
+		wx.WithRetries(3), wx.WithBackoff(0), wx.WithMaxJitter(0),
+		wx.WithOnRetry(func(n uint, err error) { onRetryCalls.Add(1) }),
+	))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+
+	span := findSpan(t, exporter.GetSpans(), "text_completion dumby model")
+	if span.Status.Code != codes.Error {
+		t.Errorf("Expected an error status, got %v", span.Status)
+	}
+	if errorType, _ := spanAttribute(span, "error.type"); errorType.AsString() != "503" {
+		t.Errorf("Expected error.type 503, got %v", errorType.Emit())
+	}
+
+	retries := 0
+	for _, event := range span.Events {
+		if event.Name == "retry" {
+			retries++
+		}
+	}
+	if retries == 0 || retries != int(onRetryCalls.Load()) {
+		t.Errorf("Expected a retry event per retry (%d), got %d", onRetryCalls.Load(), retries)
+	}
+	if onRetryCalls.Load() == 0 {
+		t.Errorf("Expected the client's OnRetryFunc to still be called")
+	}
+}
+
+func TestTelemetryTokenizeAndListModelsSpans(t *testing.T) {
+	client, exporter, reader := newTracedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		switch r.URL.Path {
+		case wx.TokenizeEndpoint:
+			w.Write([]byte(`{"model_id": "dumby model", "result": {"token_count": 3}}`))
+		case wx.FoundationModelSpecsEndpoint:
+			w.Write([]byte(`{"resources": [{"model_id": "dumby model"}]}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	if _, err := client.Tokenize("dumby model", "Hi, who are you?", false); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.ListFoundationModels(); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.ValidateModelID("dumby model"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	tokenize := findSpan(t, spans, "tokenize dumby model")
+	if operation, _ := spanAttribute(tokenize, "gen_ai.operation.name"); operation.AsString() != wx.OperationTokenize {
+		t.Errorf("Expected operation %q, got %v", wx.OperationTokenize, operation.Emit())
+	}
+
+	listed := 0
+	for _, span := range spans {
+		if span.Name == "list_models" {
+			listed++
+		}
+	}
+	if listed != 2 {
+		t.Errorf("Expected a list_models span for ListFoundationModels and ValidateModelID, got %d", listed)
+	}
+
+	duration := findMetric(t, reader, "gen_ai.client.operation.duration")
+	operations := map[string]uint64{}
+	for _, point := range duration.Data.(metricdata.Histogram[float64]).DataPoints {
+		operation, _ := point.Attributes.Value("gen_ai.operation.name")
+		operations[operation.AsString()] += point.Count
+	}
+	if operations[wx.OperationTokenize] != 1 || operations[wx.OperationListModels] != 2 {
+		t.Errorf("Expected 1 tokenize and 2 list_models measurements, got %v", operations)
+	}
+}
+
+func TestTelemetryNoopByDefault(t *testing.T) {
+	server := newTestServer(t, newUsageServer(t).ServeHTTP)
+	client := getTestClient(t, server)
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+}
@@ -1,9 +1,19 @@
 package test
 
 import (
-	wx "github.com/IBM/watsonx-go/pkg/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const (
+	TestAccessToken = "test-access-token"
+	TestProjectID   = "test-project-id"
 )
 
 func getClient(t *testing.T) *wx.Client {
@@ -26,3 +36,42 @@ func getClient(t *testing.T) *wx.Client {
 
 	return client
 }
+
+// newTestServer starts a server that issues IAM tokens and hands every other request to handler.
+func newTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	return newTestServerWithIAM(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(wx.TokenResponse{
+			AccessToken: TestAccessToken,
+			Expiration:  time.Now().Add(time.Hour).Unix(),
+		})
+	}, handler)
+}
+
+// newTestServerWithIAM starts a server that hands IAM token requests to iamHandler and every other request to handler.
+func newTestServerWithIAM(t *testing.T, iamHandler, handler http.HandlerFunc) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(wx.TokenPath, iamHandler)
+	mux.HandleFunc("/", handler)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// getTestClient creates a client that talks to the given test server for both IAM and watsonx requests.
+func getTestClient(t *testing.T, server *httptest.Server, options ...wx.ClientOption) *wx.Client {
+	options = append([]wx.ClientOption{
+		wx.WithURL(server.URL),
+		wx.WithIAM(server.URL),
+		wx.WithWatsonxAPIKey("test-api-key"),
+		wx.WithWatsonxProjectID(TestProjectID),
+	}, options...)
+
+	client, err := wx.NewClient(options...)
+	if err != nil {
+		t.Fatalf("Failed to create client for testing. Error: %v", err)
+	}
+
+	return client
+}
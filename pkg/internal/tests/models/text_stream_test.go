@@ -0,0 +1,116 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func newTextStreamServer(t *testing.T, events ...string) *wx.Client {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+
+		if r.URL.Path != wx.GenerateTextStreamEndpoint {
+			t.Errorf("Expected path %s, but got %s", wx.GenerateTextStreamEndpoint, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, event := range events {
+			fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", i+1, event)
+		}
+	})
+
+	return getTestClient(t, server)
+}
+
+func TestTextStream(t *testing.T) {
+	client := newTextStreamServer(t,
+		`{"results":[{"generated_text":"I am","generated_token_count":2,"stop_reason":"not_finished"}]}`,
+		`{"results":[{"generated_text":" a person.","generated_token_count":5,"stop_reason":"eos_token"}]}`,
+	)
+
+	stream, err := client.OpenTextStream("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	generatedText := ""
+	for stream.Next() {
+		generatedText += stream.Current().Text
+	}
+
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Expected no stream error, but got %v", err)
+	}
+	if generatedText != "I am a person." {
+		t.Fatalf("Expected generated text to be 'I am a person.', but got %s", generatedText)
+	}
+	if stream.Current().StopReason != wx.EndOfSequenceToken {
+		t.Fatalf("Expected stop reason %s, but got %s", wx.EndOfSequenceToken, stream.Current().StopReason)
+	}
+}
+
+func TestTextStreamDroppedConnection(t *testing.T) {
+	client := newTextStreamServer(t,
+		`{"results":[{"generated_text":"I am","stop_reason":"not_finished"}]}`,
+	)
+
+	stream, err := client.OpenTextStream("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+
+	if !errors.Is(stream.Err(), io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected io.ErrUnexpectedEOF, but got %v", stream.Err())
+	}
+}
+
+func TestTextStreamMalformedEvent(t *testing.T) {
+	client := newTextStreamServer(t,
+		`{"results":[{"generated_text":"I am","stop_reason":"not_finished"}]}`,
+		`{"results":`,
+	)
+
+	stream, err := client.OpenTextStream("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(stream.Err(), &syntaxErr) {
+		t.Fatalf("Expected a JSON syntax error, but got %v", stream.Err())
+	}
+}
+
+func TestGenerateTextStreamUnauthorized(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors":[{"code":"authentication_token_expired","message":"Token expired"}],"trace":"trace-1"}`))
+	})
+	client := getTestClient(t, server)
+
+	dataChan, err := client.GenerateTextStream("dumby model", "Hi, who are you?")
+
+	var apiErr *wx.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected a 401 APIError, but got %v", err)
+	}
+
+	if _, ok := <-dataChan; ok {
+		t.Fatal("Expected channel to be closed")
+	}
+}
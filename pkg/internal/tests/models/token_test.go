@@ -0,0 +1,210 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// tokenIssuer issues numbered tokens; the first one is already expired when expiredFirst is set
+type tokenIssuer struct {
+	issued       int32
+	lifetime     time.Duration
+	expiredFirst bool
+	delay        time.Duration
+}
+
+func (i *tokenIssuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt32(&i.issued, 1)
+	time.Sleep(i.delay)
+
+	expiration := time.Now().Add(i.lifetime)
+	if n == 1 && i.expiredFirst {
+		expiration = time.Now().Add(-time.Minute)
+	}
+
+	json.NewEncoder(w).Encode(wx.TokenResponse{
+		AccessToken: fmt.Sprintf("token-%d", n),
+		Expiration:  expiration.Unix(),
+	})
+}
+
+func (i *tokenIssuer) count() int32 {
+	return atomic.LoadInt32(&i.issued)
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"results":[{"generated_text":"Hello","stop_reason":"eos_token"}]}`))
+}
+
+func TestConcurrentCallsShareOneTokenRefresh(t *testing.T) {
+	issuer := &tokenIssuer{lifetime: time.Hour, expiredFirst: true, delay: 50 * time.Millisecond}
+	server := newTestServerWithIAM(t, issuer.ServeHTTP, okHandler)
+	client := getTestClient(t, server)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+				t.Errorf("Expected no error, but got an error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// one token at creation, already expired, and a single refresh for all the calls
+	if issuer.count() != 2 {
+		t.Fatalf("Expected 2 tokens to be issued, but got %d", issuer.count())
+	}
+}
+
+// waitUntil polls condition until it holds, failing the test after a few seconds
+func waitUntil(t *testing.T, condition func() bool, what string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTokenRefreshedBeforeExpiry(t *testing.T) {
+	issuer := &tokenIssuer{lifetime: 30 * time.Second}
+	var mu sync.Mutex
+	var authorizations []string
+	server := newTestServerWithIAM(t, issuer.ServeHTTP, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+		okHandler(w, r)
+	})
+	client := getTestClient(t, server, wx.WithTokenRefreshSkew(time.Minute))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	// the token expires within the skew, so it is refreshed in the background
+	// and later calls send the new token once it arrived
+	waitUntil(t, func() bool {
+		if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+			t.Fatalf("Expected no error, but got an error: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return authorizations[len(authorizations)-1] == "Bearer token-2"
+	}, "the refreshed token to be sent")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if authorizations[0] != "Bearer token-1" {
+		t.Fatalf("Expected the first call to send the cached token, but got %v", authorizations)
+	}
+}
+
+func TestEarlyRefreshDoesNotHoldUpCalls(t *testing.T) {
+	issuer := &tokenIssuer{lifetime: 30 * time.Second, delay: time.Second}
+	server := newTestServerWithIAM(t, issuer.ServeHTTP, okHandler)
+	client := getTestClient(t, server, wx.WithTokenRefreshSkew(time.Minute))
+
+	start := time.Now()
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Expected the call not to wait for IAM, but it took %v", elapsed)
+	}
+
+	waitUntil(t, func() bool { return issuer.count() == 2 }, "the background refresh")
+}
+
+func TestCachedTokenUsedWhenEarlyRefreshFails(t *testing.T) {
+	var issued atomic.Int32
+	var mu sync.Mutex
+	var authorizations []string
+	server := newTestServerWithIAM(t, func(w http.ResponseWriter, r *http.Request) {
+		if issued.Add(1) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(wx.TokenResponse{
+			AccessToken: "token-1",
+			Expiration:  time.Now().Add(30 * time.Second).Unix(),
+		})
+	}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+		okHandler(w, r)
+	})
+	client := getTestClient(t, server, wx.WithTokenRefreshSkew(time.Minute))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected the still valid token to be used, but got an error: %v", err)
+	}
+	waitUntil(t, func() bool { return issued.Load() == 2 }, "a refresh to be attempted")
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected the still valid token to be used after the failed refresh, but got an error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(authorizations) != 2 || authorizations[0] != "Bearer token-1" || authorizations[1] != "Bearer token-1" {
+		t.Fatalf("Expected the requests to be sent with the cached token, but got %v", authorizations)
+	}
+}
+
+func TestReauthenticateOnUnauthorized(t *testing.T) {
+	issuer := &tokenIssuer{lifetime: time.Hour}
+	var authorizations []string
+	server := newTestServerWithIAM(t, issuer.ServeHTTP, func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"code":"authentication_token_expired","message":"Token expired"}]}`))
+			return
+		}
+		okHandler(w, r)
+	})
+	client := getTestClient(t, server)
+
+	result, err := client.GenerateText("dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if result.Text != "Hello" {
+		t.Fatalf("Expected replayed request to succeed, but got %+v", result)
+	}
+	if len(authorizations) != 2 || authorizations[1] != "Bearer token-2" {
+		t.Fatalf("Expected the request to be replayed with a new token, but got %v", authorizations)
+	}
+}
+
+func TestBackgroundTokenRefresh(t *testing.T) {
+	issuer := &tokenIssuer{lifetime: time.Hour}
+	server := newTestServerWithIAM(t, issuer.ServeHTTP, okHandler)
+
+	// a skew larger than the lifetime makes the background refresh run at its minimum interval
+	client := getTestClient(t, server, wx.WithTokenRefreshSkew(2*time.Hour), wx.WithBackgroundTokenRefresh())
+	defer client.Close()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for issuer.count() < 2 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if issuer.count() < 2 {
+		t.Fatalf("Expected the token to be refreshed in the background, but got %d tokens", issuer.count())
+	}
+}
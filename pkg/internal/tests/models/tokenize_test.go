@@ -0,0 +1,92 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestTokenize(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wx.TokenizeEndpoint {
+			t.Errorf("Expected path %s, but got %s", wx.TokenizeEndpoint, r.URL.Path)
+		}
+
+		var payload wx.TokenizePayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Model != "dumby model" || payload.Input != "Hello, world!" || !payload.Parameters.ReturnTokens {
+			t.Errorf("Expected the model, input and return_tokens in the payload, but got %+v", payload)
+		}
+
+		w.Write([]byte(`{"model_id": "dumby model", "result": {"token_count": 4, "tokens": ["Hello", ",", " world", "!"]}}`))
+	})
+	client := getTestClient(t, server)
+
+	result, err := client.Tokenize("dumby model", "Hello, world!", true)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if result.TokenCount != 4 || len(result.Tokens) != 4 {
+		t.Fatalf("Expected 4 tokens, but got %+v", result)
+	}
+
+	if _, err := client.Tokenize("dumby model", "", false); err == nil {
+		t.Fatal("Expected an error for empty text, but got nil")
+	}
+}
+
+func TestTokenizeBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+
+		var payload wx.TokenizePayload
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		if payload.Input == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"code": "invalid_input", "message": "bad input"}]}`))
+			return
+		}
+
+		fmt.Fprintf(w, `{"result": {"token_count": %d}}`, len(strings.Fields(payload.Input)))
+	})
+	client := getTestClient(t, server)
+
+	texts := []string{"one", "one two", "fail", "one two three", "one two three four"}
+	results, err := client.TokenizeBatch("dumby model", texts, false, wx.WithTokenizeConcurrency(2))
+
+	for i, expected := range []int{1, 2, 0, 3, 4} {
+		if results[i].TokenCount != expected {
+			t.Fatalf("Expected %d tokens for text %d, but got %d", expected, i, results[i].TokenCount)
+		}
+	}
+
+	batchErrs := wx.BatchErrors(err)
+	if len(batchErrs) != 1 || batchErrs[0].Index != 2 {
+		t.Fatalf("Expected text 2 to fail, but got %v", err)
+	}
+
+	var apiErr *wx.APIError
+	if !errors.As(err, &apiErr) || !apiErr.HasCode("invalid_input") {
+		t.Fatalf("Expected an APIError with code invalid_input, but got %v", err)
+	}
+
+	if maxInFlight.Load() > 2 {
+		t.Fatalf("Expected at most 2 concurrent requests, but got %d", maxInFlight.Load())
+	}
+}
@@ -0,0 +1,163 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+const toolCallResponse = `{
+	"choices": [{
+		"index": 0,
+		"message": {"role": "assistant", "tool_calls": [{"id": "call-1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Austin\"}"}}]},
+		"finish_reason": "tool_calls"
+	}]
+}`
+
+func newWeatherRegistry(calls *int) *wx.ToolRegistry {
+	registry := wx.NewToolRegistry()
+	registry.Register(
+		wx.NewFunctionTool("get_weather", "Get the current weather of a city", map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"city": map[string]any{"type": "string"}},
+			"required":   []string{"city"},
+		}),
+		func(ctx context.Context, arguments string) (string, error) {
+			*calls++
+			var args struct {
+				City string `json:"city"`
+			}
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", err
+			}
+			return "Sunny in " + args.City, nil
+		},
+	)
+	return registry
+}
+
+func TestChatWithTools(t *testing.T) {
+	var payloads []wx.ChatPayload
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload wx.ChatPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+
+		if len(payloads) == 1 {
+			w.Write([]byte(toolCallResponse))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"It is sunny in Austin."},"finish_reason":"stop"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	calls := 0
+	result, conversation, err := client.ChatWithTools(
+		ChatModelId,
+		[]wx.ChatMessage{wx.UserMessage("What is the weather in Austin?")},
+		newWeatherRegistry(&calls),
+		wx.WithChatToolChoiceOption(wx.ToolChoiceAuto),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected handler to be called once, but got %d", calls)
+	}
+	if result.Choices[0].Message.Content != "It is sunny in Austin." {
+		t.Fatalf("Expected final answer, but got %+v", result.Choices[0].Message)
+	}
+
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 chat requests, but got %d", len(payloads))
+	}
+	if len(payloads[0].Tools) != 1 || payloads[0].Tools[0].Function.Name != "get_weather" {
+		t.Fatalf("Expected tool definitions in the request, but got %+v", payloads[0].Tools)
+	}
+	if payloads[0].ToolChoiceOption == nil || *payloads[0].ToolChoiceOption != wx.ToolChoiceAuto {
+		t.Fatalf("Expected tool choice option to be sent, but got %v", payloads[0].ToolChoiceOption)
+	}
+
+	// user, assistant tool call, tool result, final assistant answer
+	if len(conversation) != 4 {
+		t.Fatalf("Expected 4 messages in the conversation, but got %d", len(conversation))
+	}
+	toolMessage := payloads[1].Messages[2]
+	if toolMessage.Role != wx.ToolRole || toolMessage.ToolCallID != "call-1" || toolMessage.Content != "Sunny in Austin" {
+		t.Fatalf("Expected tool result to be sent back, but got %+v", toolMessage)
+	}
+}
+
+func TestChatWithToolsMaxIterations(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(toolCallResponse))
+	})
+	client := getTestClient(t, server)
+
+	calls := 0
+	_, _, err := client.ChatWithTools(
+		ChatModelId,
+		[]wx.ChatMessage{wx.UserMessage("What is the weather in Austin?")},
+		newWeatherRegistry(&calls),
+		wx.WithChatMaxToolIterations(3),
+	)
+
+	if !errors.Is(err, wx.ErrMaxToolIterations) {
+		t.Fatalf("Expected ErrMaxToolIterations, but got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("Expected handler to be called 3 times, but got %d", calls)
+	}
+}
+
+func TestChatWithToolsLeavesOptionsUntouched(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}]}`))
+	})
+	client := getTestClient(t, server)
+
+	options := make([]wx.ChatOption, 1, 2)
+	options[0] = wx.WithChatMaxToolIterations(3)
+
+	calls := 0
+	if _, _, err := client.ChatWithTools(ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")}, newWeatherRegistry(&calls), options...); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if spare := options[:2][1]; spare != nil {
+		t.Fatal("Expected the spare capacity of the options to be left untouched")
+	}
+}
+
+func TestChatWithToolsNilRegistry(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to %s", r.URL.Path)
+	})
+	client := getTestClient(t, server)
+
+	if _, _, err := client.ChatWithTools(ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")}, nil); err == nil {
+		t.Fatal("Expected an error for a nil registry, but got nil")
+	}
+}
+
+func TestToolRegistryDispatchUnknownTool(t *testing.T) {
+	registry := wx.NewToolRegistry()
+
+	messages, err := registry.Dispatch(context.Background(), []wx.ToolCall{{
+		ID:       "call-1",
+		Type:     "function",
+		Function: wx.ToolCallFunction{Name: "missing", Arguments: "{}"},
+	}})
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	if len(messages) != 1 || messages[0].ToolCallID != "call-1" || messages[0].Content == "" {
+		t.Fatalf("Expected an error tool message, but got %+v", messages)
+	}
+}
@@ -0,0 +1,166 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+// usageLog records every usage reported by a client
+type usageLog struct {
+	mu     sync.Mutex
+	usages []wx.Usage
+}
+
+func (l *usageLog) RecordUsage(ctx context.Context, usage wx.Usage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.usages = append(l.usages, usage)
+}
+
+func newUsageServer(t *testing.T) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(wx.GenerateTextEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"results": [{"generated_text": "Hello", "input_token_count": 5, "generated_token_count": 2, "stop_reason": "eos_token"}]}`))
+	})
+	mux.HandleFunc(wx.GenerateTextStreamEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\nevent: message\ndata: {\"results\":[{\"generated_text\":\"I am\",\"input_token_count\":6,\"generated_token_count\":2,\"stop_reason\":\"not_finished\"}]}\n\n")
+		fmt.Fprint(w, "id: 2\nevent: message\ndata: {\"results\":[{\"generated_text\":\" a bot.\",\"generated_token_count\":4,\"stop_reason\":\"max_tokens\"}]}\n\n")
+	})
+	mux.HandleFunc(wx.ChatEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 10, "completion_tokens": 1, "total_tokens": 11}}`))
+	})
+	mux.HandleFunc(wx.EmbeddingEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"results": [{"embedding": [0.1]}], "input_token_count": 3}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to %s", r.URL.Path)
+	})
+	return mux
+}
+
+func TestUsageRecorder(t *testing.T) {
+	log := &usageLog{}
+	server := newTestServer(t, newUsageServer(t).ServeHTTP)
+	client := getTestClient(t, server, wx.WithUsageRecorder(log))
+
+	ctx := wx.ContextWithUsageTags(context.Background(), map[string]string{"tenant": "acme"})
+	ctx = wx.ContextWithUsageTags(ctx, map[string]string{"job": "eval"})
+
+	if _, err := client.GenerateTextContext(ctx, "dumby model", "Hi, who are you?"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	stream, err := client.OpenTextStreamContext(ctx, "dumby model", "Hi, who are you?")
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	for stream.Next() {
+	}
+	stream.Close()
+
+	if _, err := client.ChatContext(ctx, ChatModelId, []wx.ChatMessage{wx.UserMessage("Hi")}); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+	if _, err := client.EmbedQueryContext(ctx, EmbeddingModelId, "Hello"); err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	expected := []wx.Usage{
+		{Operation: wx.OperationTextCompletion, Model: "dumby model", InputTokens: 5, GeneratedTokens: 2, StopReason: wx.EndOfSequenceToken},
+		{Operation: wx.OperationTextCompletion, Stream: true, Model: "dumby model", InputTokens: 6, GeneratedTokens: 4, StopReason: wx.MaxTokens},
+		{Operation: wx.OperationChat, Model: ChatModelId, InputTokens: 10, GeneratedTokens: 1, StopReason: wx.FinishStop},
+		{Operation: wx.OperationEmbeddings, Model: EmbeddingModelId, InputTokens: 3},
+	}
+
+	if len(log.usages) != len(expected) {
+		t.Fatalf("Expected %d usages, but got %d: %+v", len(expected), len(log.usages), log.usages)
+	}
+	for i, usage := range log.usages {
+		e := expected[i]
+		if usage.Operation != e.Operation || usage.Stream != e.Stream || usage.Model != e.Model ||
+			usage.InputTokens != e.InputTokens || usage.GeneratedTokens != e.GeneratedTokens || usage.StopReason != e.StopReason {
+			t.Fatalf("Expected usage %d to be %+v, but got %+v", i, e, usage)
+		}
+		if usage.Err != nil || usage.Latency <= 0 {
+			t.Fatalf("Expected usage %d to succeed with a latency, but got %+v", i, usage)
+		}
+		if usage.Tags["tenant"] != "acme" || usage.Tags["job"] != "eval" {
+			t.Fatalf("Expected usage %d to carry both tags, but got %v", i, usage.Tags)
+		}
+	}
+}
+
+func TestUsageRecorderFailure(t *testing.T) {
+	log := &usageLog{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"code": "invalid_input", "message": "bad prompt"}]}`))
+	})
+	client := getTestClient(t, server, wx.WithUsageRecorder(log))
+
+	if _, err := client.GenerateText("dumby model", "Hi, who are you?"); err == nil {
+		t.Fatal("Expected an error, but got nil")
+	}
+
+	if len(log.usages) != 1 || log.usages[0].Err == nil {
+		t.Fatalf("Expected the failed request to be recorded with its error, but got %+v", log.usages)
+	}
+}
+
+func TestUsageAggregator(t *testing.T) {
+	aggregator := wx.NewUsageAggregator(wx.PriceTable{
+		"dumby model": {InputPerMillion: 0.6, GeneratedPerMillion: 1.8},
+	})
+	server := newTestServer(t, newUsageServer(t).ServeHTTP)
+	client := getTestClient(t, server, wx.WithUsageRecorder(aggregator))
+
+	for _, tenant := range []string{"acme", "acme", "globex"} {
+		ctx := wx.ContextWithUsageTags(context.Background(), map[string]string{"tenant": tenant})
+		client.GenerateTextContext(ctx, "dumby model", "Hi, who are you?")
+	}
+	client.EmbedQuery(EmbeddingModelId, "Hello")
+
+	total := aggregator.Total()
+	if total.Requests != 4 || total.InputTokens != 18 || total.GeneratedTokens != 6 {
+		t.Fatalf("Expected 4 requests with 18 input and 6 generated tokens, but got %+v", total)
+	}
+
+	byModel := aggregator.ByModel()
+	model := byModel["dumby model"]
+	if model.Requests != 3 || model.InputTokens != 15 || model.GeneratedTokens != 6 {
+		t.Fatalf("Expected 3 generations with 15 input and 6 generated tokens, but got %+v", model)
+	}
+	if expectedCost := (15*0.6 + 6*1.8) / 1e6; math.Abs(model.EstimatedCost-expectedCost) > 1e-12 {
+		t.Fatalf("Expected an estimated cost of %v, but got %v", expectedCost, model.EstimatedCost)
+	}
+	if byModel[EmbeddingModelId].EstimatedCost != 0 {
+		t.Fatalf("Expected no cost for a model without price, but got %v", byModel[EmbeddingModelId].EstimatedCost)
+	}
+
+	byTenant := aggregator.ByTag("tenant")
+	if byTenant["acme"].Requests != 2 || byTenant["globex"].Requests != 1 {
+		t.Fatalf("Expected 2 requests for acme and 1 for globex, but got %+v", byTenant)
+	}
+
+	if models := aggregator.Models(); len(models) != 2 || models[0] != "dumby model" {
+		t.Fatalf("Expected 2 models, sorted, but got %v", models)
+	}
+
+	aggregator.Reset()
+	if aggregator.Total().Requests != 0 {
+		t.Fatalf("Expected no requests after a reset, but got %+v", aggregator.Total())
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"math"
+	"net/http"
+	"testing"
+
+	wx "github.com/IBM/watsonx-go/pkg/models"
+)
+
+func TestEmbeddingFloat32(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model_id": "dumby model", "results": [{"embedding": [0.5, -0.25], "input": "Hello"}], "input_token_count": 1}`))
+	})
+	client := getTestClient(t, server)
+
+	response, err := client.EmbedQuery(EmbeddingModelId, "Hello", wx.WithEmbeddingFloat32())
+	if err != nil {
+		t.Fatalf("Expected no error, but got an error: %v", err)
+	}
+
+	result := response.Results[0]
+	if result.Embedding != nil {
+		t.Fatalf("Expected no float64 embedding, but got %v", result.Embedding)
+	}
+	if len(result.Embedding32) != 2 || result.Embedding32[0] != 0.5 || result.Embedding32[1] != -0.25 {
+		t.Fatalf("Expected float32 embedding [0.5 -0.25], but got %v", result.Embedding32)
+	}
+	if result.Input != "Hello" || response.InputTokenCount != 1 {
+		t.Fatalf("Expected the input and token count to be decoded, but got %+v", response)
+	}
+	if v := result.Float64(); len(v) != 2 || v[0] != 0.5 {
+		t.Fatalf("Expected the float64 conversion [0.5 -0.25], but got %v", v)
+	}
+}
+
+func TestVectorSimilarity(t *testing.T) {
+	a := []float32{3, 4}
+	b := []float32{4, 3}
+
+	if dot := wx.Dot(a, b); dot != 24 {
+		t.Fatalf("Expected dot product 24, but got %v", dot)
+	}
+	if cos := wx.CosineSimilarity(a, b); math.Abs(cos-0.96) > 1e-6 {
+		t.Fatalf("Expected cosine similarity 0.96, but got %v", cos)
+	}
+	if cos := wx.CosineSimilarity(a, []float32{0, 0}); cos != 0 {
+		t.Fatalf("Expected cosine similarity 0 with a zero vector, but got %v", cos)
+	}
+
+	wx.Normalize(a)
+	if norm := wx.Norm(a); math.Abs(norm-1) > 1e-6 {
+		t.Fatalf("Expected a unit vector, but got norm %v", norm)
+	}
+	if a[0] != 0.6 || a[1] != 0.8 {
+		t.Fatalf("Expected the normalized vector [0.6 0.8], but got %v", a)
+	}
+}
+
+func TestTopKResults(t *testing.T) {
+	results := []wx.EmbeddingResult{
+		{Embedding: []float64{0, 1}},
+		{Embedding: []float64{1, 0}},
+		{Embedding: nil}, // failed
+		{Embedding: []float64{1, 1}},
+		{Embedding: []float64{-1, 0}},
+	}
+	query := wx.EmbeddingResult{Embedding: []float64{1, 0.1}}
+
+	matches := wx.TopKResults(query, results, 3)
+	if len(matches) != 3 {
+		t.Fatalf("Expected 3 matches, but got %v", matches)
+	}
+	for i, expected := range []int{1, 3, 0} {
+		if matches[i].Index != expected {
+			t.Fatalf("Expected match %d to be result %d, but got %v", i, expected, matches)
+		}
+	}
+
+	query32 := wx.EmbeddingResult{Embedding32: []float32{-1, 0}}
+	matches = wx.TopKResults(query32, results, 1)
+	if len(matches) != 1 || matches[0].Index != 4 || math.Abs(matches[0].Score-1) > 1e-6 {
+		t.Fatalf("Expected result 4 to match with score 1, but got %v", matches)
+	}
+
+	if matches := wx.TopK([]float64{1}, [][]float64{{1}, {2}}, 5, wx.Dot[float64]); len(matches) != 2 || matches[0].Index != 1 {
+		t.Fatalf("Expected both candidates by dot product, but got %v", matches)
+	}
+}
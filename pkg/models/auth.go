@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Authenticator obtains the bearer tokens sent to watsonx.
+// The client caches the tokens, refreshes them ahead of their expiration and
+// authenticates again when a token is rejected, so Authenticate is only called when a new token is needed.
+type Authenticator interface {
+	// Authenticate fetches a new token, sending any request through client.
+	// An empty token value sends requests without an Authorization header.
+	Authenticate(ctx context.Context, client Doer) (IAMToken, error)
+}
+
+// IAMAuthenticator authenticates with an IBM Cloud API key against IAM
+type IAMAuthenticator struct {
+	apiKey WatsonxAPIKey
+	iam    string
+}
+
+// NewIAMAuthenticator creates an IBM Cloud IAM authenticator; an empty iam host defaults to IAMCloudHost
+func NewIAMAuthenticator(apiKey WatsonxAPIKey, iam string) *IAMAuthenticator {
+	if iam == "" {
+		iam = IAMCloudHost
+	}
+	return &IAMAuthenticator{apiKey, iam}
+}
+
+func (a *IAMAuthenticator) Authenticate(ctx context.Context, client Doer) (IAMToken, error) {
+	return GenerateTokenContext(ctx, client, a.apiKey, a.iam)
+}
+
+// BearerTokenAuthenticator sends a token obtained elsewhere, as is
+type BearerTokenAuthenticator struct {
+	token string
+}
+
+func NewBearerTokenAuthenticator(token string) *BearerTokenAuthenticator {
+	return &BearerTokenAuthenticator{token}
+}
+
+func (a *BearerTokenAuthenticator) Authenticate(ctx context.Context, client Doer) (IAMToken, error) {
+	return NewIAMToken(a.token, time.Time{}), nil
+}
+
+// NoAuthAuthenticator sends requests without credentials, e.g. to a gateway that authenticates on its own
+type NoAuthAuthenticator struct{}
+
+func NewNoAuthAuthenticator() *NoAuthAuthenticator {
+	return &NoAuthAuthenticator{}
+}
+
+func (a *NoAuthAuthenticator) Authenticate(ctx context.Context, client Doer) (IAMToken, error) {
+	return IAMToken{}, nil
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	DefaultBatchConcurrency = 8
+)
+
+// BatchError is the failure of one item of a batch call, at its index in the inputs
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchErrors returns the failed items of an error returned by a batch call, ordered by index
+func BatchErrors(err error) []*BatchError {
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			return []*BatchError{batchErr}
+		}
+		return nil
+	}
+
+	var batchErrs []*BatchError
+	for _, err := range joined.Unwrap() {
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			batchErrs = append(batchErrs, batchErr)
+		}
+	}
+	return batchErrs
+}
+
+// runBatch calls fn for the items 0 to n-1 with at most concurrency calls at once.
+// Items not started when ctx is done fail with the context's error.
+// The failures are joined as BatchErrors ordered by index.
+func runBatch(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			errs[i] = &BatchError{Index: i, Err: err}
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = &BatchError{Index: i, Err: ctx.Err()}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				errs[i] = &BatchError{Index: i, Err: err}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,273 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	ChatEndpoint string = GenerationEndpoint + "/chat"
+)
+
+type ChatRole = string
+
+const (
+	SystemRole    ChatRole = "system"
+	UserRole      ChatRole = "user"
+	AssistantRole ChatRole = "assistant"
+	ToolRole      ChatRole = "tool"
+)
+
+type FinishReason = string
+
+const (
+	FinishStop      FinishReason = "stop"       // Model hit a natural stop point or a stop sequence
+	FinishLength    FinishReason = "length"     // Maximum requested tokens reached
+	FinishToolCalls FinishReason = "tool_calls" // Model called one or more tools
+	FinishTimeLimit FinishReason = "time_limit" // Time limit reached
+	FinishCancelled FinishReason = "cancelled"  // Request canceled by the client
+	FinishError     FinishReason = "error"      // Error encountered
+)
+
+type ChatContentType = string
+
+const (
+	TextContent     ChatContentType = "text"
+	ImageURLContent ChatContentType = "image_url"
+)
+
+// ChatContentPart is one piece of a multi-part user message
+type ChatContentPart struct {
+	Type     ChatContentType `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *ChatImageURL   `json:"image_url,omitempty"`
+}
+
+type ChatImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON encoded arguments
+}
+
+// ChatMessage is a single message of a conversation.
+// Content holds plain text; Parts, if set, takes precedence and is sent as a list of content parts.
+type ChatMessage struct {
+	Role       ChatRole
+	Content    string
+	Parts      []ChatContentPart
+	Name       string
+	ToolCalls  []ToolCall
+	ToolCallID string
+}
+
+type chatMessageJSON struct {
+	Role       ChatRole        `json:"role"`
+	Content    json.RawMessage `json:"content,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+func (cm ChatMessage) MarshalJSON() ([]byte, error) {
+	var content any = cm.Content
+	if len(cm.Parts) > 0 {
+		content = cm.Parts
+	} else if cm.Content == "" && len(cm.ToolCalls) > 0 {
+		content = nil
+	}
+
+	var raw json.RawMessage
+	if content != nil {
+		var err error
+		if raw, err = json.Marshal(content); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(chatMessageJSON{
+		Role:       cm.Role,
+		Content:    raw,
+		Name:       cm.Name,
+		ToolCalls:  cm.ToolCalls,
+		ToolCallID: cm.ToolCallID,
+	})
+}
+
+func (cm *ChatMessage) UnmarshalJSON(data []byte) error {
+	var msg chatMessageJSON
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+
+	*cm = ChatMessage{
+		Role:       msg.Role,
+		Name:       msg.Name,
+		ToolCalls:  msg.ToolCalls,
+		ToolCallID: msg.ToolCallID,
+	}
+
+	if len(msg.Content) == 0 || string(msg.Content) == "null" {
+		return nil
+	}
+	if msg.Content[0] == '[' {
+		return json.Unmarshal(msg.Content, &cm.Parts)
+	}
+	return json.Unmarshal(msg.Content, &cm.Content)
+}
+
+func SystemMessage(content string) ChatMessage {
+	return ChatMessage{Role: SystemRole, Content: content}
+}
+
+func UserMessage(content string) ChatMessage {
+	return ChatMessage{Role: UserRole, Content: content}
+}
+
+// UserMessageParts creates a user message made of multiple content parts, e.g. text and images
+func UserMessageParts(parts ...ChatContentPart) ChatMessage {
+	return ChatMessage{Role: UserRole, Parts: parts}
+}
+
+func AssistantMessage(content string) ChatMessage {
+	return ChatMessage{Role: AssistantRole, Content: content}
+}
+
+// ToolMessage creates the message carrying the result of the tool call with the given ID
+func ToolMessage(toolCallID, content string) ChatMessage {
+	return ChatMessage{Role: ToolRole, Content: content, ToolCallID: toolCallID}
+}
+
+func TextPart(text string) ChatContentPart {
+	return ChatContentPart{Type: TextContent, Text: text}
+}
+
+func ImageURLPart(url string) ChatContentPart {
+	return ChatContentPart{Type: ImageURLContent, ImageURL: &ChatImageURL{URL: url}}
+}
+
+type ChatPayload struct {
+	ProjectID string        `json:"project_id,omitempty"`
+	SpaceID   string        `json:"space_id,omitempty"`
+	Model     string        `json:"model_id"`
+	Messages  []ChatMessage `json:"messages"`
+	*ChatOptions
+}
+
+type ChatResult struct {
+	ID           string       `json:"id"`
+	Model        string       `json:"model_id"`
+	ModelVersion string       `json:"model_version,omitempty"`
+	Choices      []ChatChoice `json:"choices"`
+	Created      int64        `json:"created"`
+	CreatedAt    time.Time    `json:"created_at"`
+	Usage        ChatUsage    `json:"usage"`
+}
+
+type ChatChoice struct {
+	Index        int          `json:"index"`
+	Message      ChatMessage  `json:"message"`
+	FinishReason FinishReason `json:"finish_reason"`
+}
+
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Chat generates the next message of a conversation
+func (m *Client) Chat(model string, messages []ChatMessage, options ...ChatOption) (ChatResult, error) {
+	return m.ChatContext(context.Background(), model, messages, options...)
+}
+
+// ChatContext is like Chat but binds the request to ctx
+func (m *Client) ChatContext(ctx context.Context, model string, messages []ChatMessage, options ...ChatOption) (ChatResult, error) {
+	if len(messages) == 0 {
+		return ChatResult{}, errors.New("messages cannot be empty")
+	}
+
+	opts := &ChatOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return ChatResult{}, err
+	}
+
+	payload := ChatPayload{
+		ProjectID:   projectID,
+		SpaceID:     spaceID,
+		Model:       model,
+		Messages:    messages,
+		ChatOptions: opts,
+	}
+
+	ctx, call := m.startCall(ctx, Usage{Operation: OperationChat, Model: model})
+	result, err := m.generateChatRequest(ctx, payload)
+
+	usage := chatUsage(model, result.Usage, err)
+	if len(result.Choices) > 0 {
+		usage.StopReason = result.Choices[0].FinishReason
+	}
+	call.end(usage)
+
+	if err != nil {
+		return ChatResult{}, err
+	}
+
+	if len(result.Choices) == 0 {
+		return ChatResult{}, errors.New("no result received")
+	}
+
+	return result, nil
+}
+
+func chatUsage(model string, tokens ChatUsage, err error) Usage {
+	return Usage{
+		Operation:       OperationChat,
+		Model:           model,
+		InputTokens:     tokens.PromptTokens,
+		GeneratedTokens: tokens.CompletionTokens,
+		Err:             err,
+	}
+}
+
+// generateChatRequest sends the chat request and decodes the response.
+// Returns error on non-2XX response
+func (m *Client) generateChatRequest(ctx context.Context, payload ChatPayload) (ChatResult, error) {
+	req, err := m.newJSONRequest(ctx, http.MethodPost, ChatEndpoint, payload)
+	if err != nil {
+		return ChatResult{}, err
+	}
+
+	res, err := m.doRequest(req)
+	if err != nil {
+		return ChatResult{}, err
+	}
+	defer res.Body.Close()
+
+	var chatRes ChatResult
+
+	if err := json.NewDecoder(res.Body).Decode(&chatRes); err != nil {
+		return ChatResult{}, err
+	}
+
+	return chatRes, nil
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+)
+
+type ChatOption func(*ChatOptions)
+
+type ChatResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type ChatOptions struct {
+	// https://cloud.ibm.com/apidocs/watsonx-ai#text-chat
+	FrequencyPenalty *float64            `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64            `json:"presence_penalty,omitempty"`
+	LogProbs         *bool               `json:"logprobs,omitempty"`
+	TopLogProbs      *uint               `json:"top_logprobs,omitempty"`
+	MaxTokens        *uint               `json:"max_tokens,omitempty"`
+	N                *uint               `json:"n,omitempty"`
+	Temperature      *float64            `json:"temperature,omitempty"`
+	TopP             *float64            `json:"top_p,omitempty"`
+	Seed             *uint               `json:"seed,omitempty"`
+	Stop             *[]string           `json:"stop,omitempty"`
+	TimeLimit        *uint               `json:"time_limit,omitempty"`
+	ResponseFormat   *ChatResponseFormat `json:"response_format,omitempty"`
+	Tools            []Tool              `json:"tools,omitempty"`
+	ToolChoiceOption *ToolChoiceOption   `json:"tool_choice_option,omitempty"`
+	ToolChoice       *ToolChoice         `json:"tool_choice,omitempty"`
+
+	maxToolIterations uint // Only used by ChatWithTools
+	scope             callScope
+}
+
+func WithChatFrequencyPenalty(frequencyPenalty float64) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.FrequencyPenalty = &frequencyPenalty
+	}
+}
+
+func WithChatPresencePenalty(presencePenalty float64) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.PresencePenalty = &presencePenalty
+	}
+}
+
+func WithChatLogProbs(topLogProbs uint) ChatOption {
+	return func(opts *ChatOptions) {
+		logProbs := true
+		opts.LogProbs = &logProbs
+		opts.TopLogProbs = &topLogProbs
+	}
+}
+
+func WithChatMaxTokens(maxTokens uint) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.MaxTokens = &maxTokens
+	}
+}
+
+func WithChatN(n uint) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.N = &n
+	}
+}
+
+func WithChatTemperature(temperature float64) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.Temperature = &temperature
+	}
+}
+
+func WithChatTopP(topP float64) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.TopP = &topP
+	}
+}
+
+func WithChatSeed(seed uint) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.Seed = &seed
+	}
+}
+
+func WithChatStop(stop []string) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.Stop = &stop
+	}
+}
+
+func WithChatTimeLimit(timeLimit uint) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.TimeLimit = &timeLimit
+	}
+}
+
+// WithChatResponseFormat sets the response format type, e.g. "json_object"
+func WithChatResponseFormat(formatType string) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.ResponseFormat = &ChatResponseFormat{formatType}
+	}
+}
+
+func WithChatTools(tools ...Tool) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.Tools = tools
+	}
+}
+
+// WithChatToolChoiceOption sets whether the model may, must or must not call tools
+func WithChatToolChoiceOption(toolChoiceOption ToolChoiceOption) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.ToolChoiceOption = &toolChoiceOption
+	}
+}
+
+// WithChatToolChoice forces the model to call the named function
+func WithChatToolChoice(functionName string) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.ToolChoice = &ToolChoice{FunctionToolType, ToolChoiceFunction{functionName}}
+	}
+}
+
+// WithChatMaxToolIterations limits how many rounds of tool calls ChatWithTools performs
+func WithChatMaxToolIterations(maxToolIterations uint) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.maxToolIterations = maxToolIterations
+	}
+}
+
+// WithChatProjectID runs the call in the given project instead of the client's project or space;
+// it cannot be combined with WithChatSpaceID
+func WithChatProjectID(projectID WatsonxProjectID) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.scope.projectID = projectID
+	}
+}
+
+// WithChatSpaceID runs the call in the given deployment space instead of the client's project or space;
+// it cannot be combined with WithChatProjectID
+func WithChatSpaceID(spaceID WatsonxSpaceID) ChatOption {
+	return func(opts *ChatOptions) {
+		opts.scope.spaceID = spaceID
+	}
+}
+
+func (cp *ChatOptions) String() string {
+	return fmt.Sprintf(
+		"frequencyPenalty: %v\n"+
+			"presencePenalty: %v\n"+
+			"logProbs: %v\n"+
+			"topLogProbs: %v\n"+
+			"maxTokens: %v\n"+
+			"n: %v\n"+
+			"temperature: %v\n"+
+			"topP: %v\n"+
+			"seed: %v\n"+
+			"stop: %v\n"+
+			"timeLimit: %v\n"+
+			"responseFormat: %v\n"+
+			"tools: %v\n"+
+			"toolChoiceOption: %v\n"+
+			"toolChoice: %v",
+		cp.FrequencyPenalty,
+		cp.PresencePenalty,
+		cp.LogProbs,
+		cp.TopLogProbs,
+		cp.MaxTokens,
+		cp.N,
+		cp.Temperature,
+		cp.TopP,
+		cp.Seed,
+		cp.Stop,
+		cp.TimeLimit,
+		cp.ResponseFormat,
+		cp.Tools,
+		cp.ToolChoiceOption,
+		cp.ToolChoice,
+	)
+}
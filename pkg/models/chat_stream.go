@@ -0,0 +1,265 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ChatStreamEndpoint string = GenerationEndpoint + "/chat_stream"
+)
+
+type ChatStreamChunk struct {
+	ID           string             `json:"id"`
+	Model        string             `json:"model_id"`
+	ModelVersion string             `json:"model_version,omitempty"`
+	Choices      []ChatStreamChoice `json:"choices"`
+	Created      int64              `json:"created"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Usage        *ChatUsage         `json:"usage,omitempty"` // Usually only set on the last chunk
+}
+
+type ChatStreamChoice struct {
+	Index        int              `json:"index"`
+	Delta        ChatMessageDelta `json:"delta"`
+	FinishReason FinishReason     `json:"finish_reason"`
+}
+
+// ChatMessageDelta is the increment of a message carried by a single chunk
+type ChatMessageDelta struct {
+	Role      ChatRole        `json:"role,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta is a fragment of a tool call; fragments with the same Index belong to the same call
+type ToolCallDelta struct {
+	Index    int              `json:"index"`
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ChatCompletionStream iterates over the chunks of a streamed chat completion.
+//
+//	for stream.Next() {
+//		chunk := stream.Current()
+//	}
+//	if err := stream.Err(); err != nil { ... }
+//
+// Err is nil once a finish_reason was received, an *APIError for watsonx errors, and
+// io.ErrUnexpectedEOF when the connection dropped before the message finished.
+type ChatCompletionStream struct {
+	*eventStream
+	current ChatStreamChunk
+
+	usage        ChatUsage
+	finishReason FinishReason
+}
+
+// Next advances the stream to the next chunk; it returns false when the stream ends or fails
+func (s *ChatCompletionStream) Next() bool {
+	data, ok := s.next()
+	if !ok {
+		if s.err == nil && s.finishReason == "" {
+			s.err = io.ErrUnexpectedEOF
+		}
+		s.end()
+		return false
+	}
+
+	var chunk ChatStreamChunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		s.err = err
+		s.end()
+		return false
+	}
+
+	s.current = chunk
+	if chunk.Usage != nil {
+		s.usage = *chunk.Usage
+	}
+	if len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != "" {
+		s.finishReason = chunk.Choices[0].FinishReason
+	}
+	return true
+}
+
+// Current returns the chunk read by the last call to Next
+func (s *ChatCompletionStream) Current() ChatStreamChunk {
+	return s.current
+}
+
+// ChatStream streams the next message of a conversation
+func (m *Client) ChatStream(model string, messages []ChatMessage, options ...ChatOption) (*ChatCompletionStream, error) {
+	return m.ChatStreamContext(context.Background(), model, messages, options...)
+}
+
+// ChatStreamContext is like ChatStream but binds the stream to ctx.
+// Cancelling ctx aborts the request and ends the stream.
+func (m *Client) ChatStreamContext(ctx context.Context, model string, messages []ChatMessage, options ...ChatOption) (*ChatCompletionStream, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("messages cannot be empty")
+	}
+
+	opts := &ChatOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := ChatPayload{
+		ProjectID:   projectID,
+		SpaceID:     spaceID,
+		Model:       model,
+		Messages:    messages,
+		ChatOptions: opts,
+	}
+
+	ctx, call := m.startCall(ctx, Usage{Operation: OperationChat, Stream: true, Model: model})
+
+	req, err := m.newJSONRequest(ctx, http.MethodPost, ChatStreamEndpoint, payload)
+	if err == nil {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	var res *http.Response
+	if err == nil {
+		res, err = m.doRequest(req)
+	}
+	if err != nil {
+		usage := chatUsage(model, ChatUsage{}, err)
+		usage.Stream = true
+		call.end(usage)
+		return nil, err
+	}
+
+	stream := &ChatCompletionStream{eventStream: newEventStream(res.Body)}
+	stream.onFirstData = call.firstChunk
+	stream.onEnd = func() {
+		usage := chatUsage(model, stream.usage, stream.err)
+		usage.Stream = true
+		usage.StopReason = stream.finishReason
+		call.end(usage)
+	}
+
+	return stream, nil
+}
+
+// ChatStreamAccumulator rebuilds complete messages from streamed chunks, including tool calls
+// whose arguments arrive in fragments.
+type ChatStreamAccumulator struct {
+	result    ChatResult
+	choices   map[int]*ChatChoice
+	content   map[int]*strings.Builder
+	toolCalls map[int]map[int]*ToolCall
+}
+
+func NewChatStreamAccumulator() *ChatStreamAccumulator {
+	return &ChatStreamAccumulator{
+		choices:   make(map[int]*ChatChoice),
+		content:   make(map[int]*strings.Builder),
+		toolCalls: make(map[int]map[int]*ToolCall),
+	}
+}
+
+// Add merges a chunk into the accumulated result
+func (a *ChatStreamAccumulator) Add(chunk ChatStreamChunk) {
+	if chunk.ID != "" {
+		a.result.ID = chunk.ID
+	}
+	if chunk.Model != "" {
+		a.result.Model = chunk.Model
+	}
+	if chunk.ModelVersion != "" {
+		a.result.ModelVersion = chunk.ModelVersion
+	}
+	if chunk.Created != 0 {
+		a.result.Created = chunk.Created
+	}
+	if !chunk.CreatedAt.IsZero() {
+		a.result.CreatedAt = chunk.CreatedAt
+	}
+	if chunk.Usage != nil {
+		a.result.Usage = *chunk.Usage
+	}
+
+	for _, delta := range chunk.Choices {
+		choice, ok := a.choices[delta.Index]
+		if !ok {
+			choice = &ChatChoice{Index: delta.Index, Message: ChatMessage{Role: AssistantRole}}
+			a.choices[delta.Index] = choice
+			a.content[delta.Index] = &strings.Builder{}
+			a.toolCalls[delta.Index] = make(map[int]*ToolCall)
+		}
+
+		if delta.Delta.Role != "" {
+			choice.Message.Role = delta.Delta.Role
+		}
+		if delta.FinishReason != "" {
+			choice.FinishReason = delta.FinishReason
+		}
+		a.content[delta.Index].WriteString(delta.Delta.Content)
+
+		for _, fragment := range delta.Delta.ToolCalls {
+			call, ok := a.toolCalls[delta.Index][fragment.Index]
+			if !ok {
+				call = &ToolCall{}
+				a.toolCalls[delta.Index][fragment.Index] = call
+			}
+			if fragment.ID != "" {
+				call.ID = fragment.ID
+			}
+			if fragment.Type != "" {
+				call.Type = fragment.Type
+			}
+			call.Function.Name += fragment.Function.Name
+			call.Function.Arguments += fragment.Function.Arguments
+		}
+	}
+}
+
+// Result returns the result accumulated so far, with choices ordered by index
+func (a *ChatStreamAccumulator) Result() ChatResult {
+	result := a.result
+	result.Choices = make([]ChatChoice, 0, len(a.choices))
+
+	for index, choice := range a.choices {
+		c := *choice
+		c.Message.Content = a.content[index].String()
+
+		calls := a.toolCalls[index]
+		if len(calls) > 0 {
+			callIndexes := make([]int, 0, len(calls))
+			for callIndex := range calls {
+				callIndexes = append(callIndexes, callIndex)
+			}
+			sort.Ints(callIndexes)
+
+			c.Message.ToolCalls = make([]ToolCall, 0, len(calls))
+			for _, callIndex := range callIndexes {
+				c.Message.ToolCalls = append(c.Message.ToolCalls, *calls[callIndex])
+			}
+		}
+
+		result.Choices = append(result.Choices, c)
+	}
+
+	sort.Slice(result.Choices, func(i, j int) bool {
+		return result.Choices[i].Index < result.Choices[j].Index
+	})
+
+	return result
+}
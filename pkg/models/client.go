@@ -1,10 +1,19 @@
 package models
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"net/url"
 	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -13,15 +22,22 @@ const (
 
 type Client struct {
 	url        string
-	iam        string
 	region     IBMCloudRegion
 	apiVersion string
 
-	token     IAMToken
-	apiKey    WatsonxAPIKey
+	tokens    *tokenSource
 	projectID WatsonxProjectID
+	spaceID   WatsonxSpaceID
+
+	httpClient   Doer
+	retryOptions []RetryOption
 
-	httpClient Doer
+	embeddingCache EmbeddingCache
+	limiter        *rateLimiter
+	usageRecorder  UsageRecorder
+	telemetry      *telemetry
+	logger         *slog.Logger
+	redactPrompts  bool
 }
 
 func NewClient(options ...ClientOption) (*Client, error) {
@@ -43,67 +59,244 @@ func NewClient(options ...ClientOption) (*Client, error) {
 		opts.IAM = IAMCloudHost
 	}
 
-	if opts.apiKey == "" {
-		return nil, errors.New("no watsonx API key provided")
+	if opts.authenticator == nil {
+		if opts.apiKey == "" {
+			return nil, errors.New("no watsonx API key provided")
+		}
+		opts.authenticator = NewIAMAuthenticator(opts.apiKey, opts.IAM)
 	}
 
-	if opts.projectID == "" {
-		return nil, errors.New("no watsonx project ID provided")
+	if err := resolveScope(opts); err != nil {
+		return nil, err
 	}
 
 	m := &Client{
 		url:        opts.URL,
-		iam:        opts.IAM,
 		region:     opts.Region,
 		apiVersion: opts.APIVersion,
 
-		// token: set below
-		apiKey:    opts.apiKey,
+		// tokens: set below
 		projectID: opts.projectID,
+		spaceID:   opts.spaceID,
+
+		httpClient:   opts.doer,
+		retryOptions: opts.retryOptions,
+
+		embeddingCache: opts.embeddingCache,
+		usageRecorder:  opts.usageRecorder,
+		telemetry:      newTelemetry(opts.tracerProvider, opts.meterProvider),
+		redactPrompts:  opts.redactPrompts,
+	}
+
+	apiKey := opts.apiKey
+	m.logger = newRedactingLogger(opts.logger, func() []string {
+		return []string{apiKey, m.tokens.current()}
+	})
+
+	if len(opts.rateLimitOptions) > 0 {
+		m.limiter = newRateLimiter(opts.rateLimitOptions...)
+	}
+
+	if m.httpClient == nil {
+		m.httpClient = NewHttpClientWith(opts.httpClient)
+	}
 
-		httpClient: NewHttpClient(),
+	if opts.logger != nil {
+		m.httpClient = &loggingDoer{Doer: m.httpClient, logger: m.logger}
 	}
 
+	authenticator := opts.authenticator
+	m.tokens = newTokenSource(func(ctx context.Context) (IAMToken, error) {
+		return m.authenticate(ctx, authenticator)
+	}, opts.tokenRefreshSkew)
+
 	err := m.RefreshToken()
 	if err != nil {
 		return nil, err
 	}
 
+	if opts.backgroundTokenRefresh {
+		m.tokens.StartBackgroundRefresh()
+	}
+
 	return m, nil
 }
 
-// CheckAndRefreshToken checks the IAM token if it expired; if it did, it refreshes it; nothing if not
-func (m *Client) CheckAndRefreshToken() error {
-	if m.token.Expired() {
-		return m.RefreshToken()
-	}
+// Close releases the resources of the client, such as the background token refresh
+func (m *Client) Close() error {
+	m.tokens.Close()
 	return nil
 }
 
+// CheckAndRefreshToken checks the IAM token if it expired or is about to; if it did, it refreshes it; nothing if not
+func (m *Client) CheckAndRefreshToken() error {
+	return m.CheckAndRefreshTokenContext(context.Background())
+}
+
+// CheckAndRefreshTokenContext is like CheckAndRefreshToken but uses ctx for the IAM request
+func (m *Client) CheckAndRefreshTokenContext(ctx context.Context) error {
+	_, err := m.tokens.Token(ctx)
+	return err
+}
+
 // RefreshToken generates and sets the model with a new token
 func (m *Client) RefreshToken() error {
-	token, err := GenerateToken(m.httpClient, m.apiKey, m.iam)
-	if err != nil {
-		return err
-	}
-	m.token = token
-	return nil
+	return m.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx for the IAM request
+func (m *Client) RefreshTokenContext(ctx context.Context) error {
+	_, err := m.tokens.Refresh(ctx)
+	return err
 }
 
 // generateUrlFromEndpoint generates a URL from the endpoint and the client's configuration
 func (m *Client) generateUrlFromEndpoint(endpoint string) string {
-	params := url.Values{
+	return m.generateUrlWithParams(endpoint, nil)
+}
+
+// generateUrlWithParams is like generateUrlFromEndpoint but adds the query parameters
+func (m *Client) generateUrlWithParams(endpoint string, params url.Values) string {
+	query := url.Values{
 		"version": {m.apiVersion},
 	}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	return buildURL(m.url, endpoint, query)
+}
+
+// newJSONRequest creates an authenticated request bound to ctx, with the payload encoded as JSON
+func (m *Client) newJSONRequest(ctx context.Context, method, endpoint string, payload any) (*http.Request, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := m.newRequest(ctx, method, m.generateUrlFromEndpoint(endpoint), bytes.NewBuffer(payloadJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	m.logRequest(ctx, method, endpoint, payloadJSON)
+
+	return req, nil
+}
+
+// newRequest creates an authenticated request to rawURL bound to ctx
+func (m *Client) newRequest(ctx context.Context, method, rawURL string, body io.Reader) (*http.Request, error) {
+	token, err := m.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the client's policy goes first so that the call's own policy overrides it
+	policy := append(append([]RetryOption(nil), m.retryOptions...), RetryPolicyFromContext(ctx)...)
+	if trace.SpanFromContext(ctx).IsRecording() {
+		policy = append(policy, retrySpanEvents(ctx, policy))
+	}
+	if m.logger.Enabled(ctx, slog.LevelDebug) {
+		policy = append(policy, m.logRetries(ctx, method, rawURL, policy))
+	}
+	if m.limiter != nil {
+		policy = append(policy, withBeforeRetry(m.limiter.waitRequest))
+	}
+	if len(policy) > 0 {
+		ctx = context.WithValue(ctx, retryPolicyKey{}, policy)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return req, nil
+}
+
+// doRequest sends the request with retries once the rate limiter allows it, each retry waiting for it too.
+// When the token is rejected with a 401, it re-authenticates once and replays the request.
+func (m *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if m.limiter == nil {
+		return m.doRequestWithReauth(req)
+	}
+
+	release, err := m.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := m.doRequestWithReauth(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
 
-	generateTextURL := url.URL{
+	// the call holds its concurrency slot until its response, or stream, is closed
+	res.Body = &releaseOnClose{ReadCloser: res.Body, release: release}
+
+	return res, nil
+}
+
+func (m *Client) doRequestWithReauth(req *http.Request) (*http.Response, error) {
+	res, err := m.httpClient.DoWithRetry(req)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	m.tokens.Invalidate(rejected)
+
+	token, tokenErr := m.tokens.Token(req.Context())
+	if tokenErr != nil || token == rejected {
+		return nil, err
+	}
+
+	replay, replayErr := rewindRequest(req)
+	if replayErr != nil {
+		return nil, err
+	}
+	if m.limiter != nil {
+		if waitErr := m.limiter.waitRequest(req.Context()); waitErr != nil {
+			return nil, waitErr
+		}
+	}
+	if token != "" {
+		replay.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return m.httpClient.DoWithRetry(replay)
+}
+
+// buildURL joins a host and a path into an absolute URL.
+// The host defaults to https but may carry its own scheme, e.g. "http://localhost:8080"
+func buildURL(host, path string, params url.Values) string {
+	u := url.URL{
 		Scheme:   "https",
-		Host:     m.url,
-		Path:     endpoint,
+		Host:     host,
+		Path:     path,
 		RawQuery: params.Encode(),
 	}
 
-	return generateTextURL.String()
+	// keep the escaping of paths with escaped segments, e.g. a deployment ID containing a slash
+	if unescaped, err := url.PathUnescape(path); err == nil && unescaped != path {
+		u.Path, u.RawPath = unescaped, path
+	}
+
+	if scheme, rest, ok := strings.Cut(host, "://"); ok {
+		u.Scheme = scheme
+		u.Host = rest
+	}
+
+	return u.String()
 }
 
 func buildBaseURL(region IBMCloudRegion) string {
@@ -119,5 +312,8 @@ func defaulClientOptions() *ClientOptions {
 
 		apiKey:    os.Getenv(WatsonxAPIKeyEnvVarName),
 		projectID: os.Getenv(WatsonxProjectIDEnvVarName),
+		spaceID:   os.Getenv(WatsonxSpaceIDEnvVarName),
+
+		tokenRefreshSkew: DefaultTokenRefreshSkew,
 	}
 }
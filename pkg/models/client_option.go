@@ -1,5 +1,14 @@
 package models
 
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
 type ClientOption func(*ClientOptions)
 
 type ClientOptions struct {
@@ -8,8 +17,27 @@ type ClientOptions struct {
 	Region     IBMCloudRegion
 	APIVersion string
 
-	apiKey    WatsonxAPIKey
-	projectID WatsonxProjectID
+	apiKey       WatsonxAPIKey
+	projectID    WatsonxProjectID
+	spaceID      WatsonxSpaceID
+	projectIDSet bool // set by WithWatsonxProjectID rather than the environment
+	spaceIDSet   bool // set by WithWatsonxSpaceID rather than the environment
+
+	httpClient    *http.Client
+	doer          Doer
+	authenticator Authenticator
+	retryOptions  []RetryOption
+
+	tokenRefreshSkew       time.Duration
+	backgroundTokenRefresh bool
+
+	embeddingCache   EmbeddingCache
+	rateLimitOptions []RateLimitOption
+	usageRecorder    UsageRecorder
+	tracerProvider   trace.TracerProvider
+	meterProvider    metric.MeterProvider
+	logger           *slog.Logger
+	redactPrompts    bool
 }
 
 func WithURL(url string) ClientOption {
@@ -42,8 +70,123 @@ func WithWatsonxAPIKey(watsonxAPIKey WatsonxAPIKey) ClientOption {
 	}
 }
 
+// WithWatsonxProjectID sets the project the calls run in. It overrides a space ID
+// from the environment; it cannot be combined with WithWatsonxSpaceID.
 func WithWatsonxProjectID(projectID WatsonxProjectID) ClientOption {
 	return func(o *ClientOptions) {
 		o.projectID = projectID
+		o.projectIDSet = true
+	}
+}
+
+// WithWatsonxSpaceID sets the deployment space the calls run in. It overrides a project ID
+// from the environment; it cannot be combined with WithWatsonxProjectID.
+func WithWatsonxSpaceID(spaceID WatsonxSpaceID) ClientOption {
+	return func(o *ClientOptions) {
+		o.spaceID = spaceID
+		o.spaceIDSet = true
+	}
+}
+
+// WithHTTPClient sets the http.Client used for IAM and watsonx requests,
+// e.g. to configure timeouts, proxies or TLS
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *ClientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithDoer replaces the transport of the client altogether; it takes precedence over WithHTTPClient
+func WithDoer(doer Doer) ClientOption {
+	return func(o *ClientOptions) {
+		o.doer = doer
+	}
+}
+
+// WithRetryPolicy sets the retry options of every call made by the client.
+// Calls may override them with ContextWithRetryPolicy.
+func WithRetryPolicy(options ...RetryOption) ClientOption {
+	return func(o *ClientOptions) {
+		o.retryOptions = append(o.retryOptions, options...)
+	}
+}
+
+// WithTokenRefreshSkew sets how long before its expiration the IAM token is refreshed
+func WithTokenRefreshSkew(skew time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.tokenRefreshSkew = skew
+	}
+}
+
+// WithBackgroundTokenRefresh refreshes the IAM token in the background ahead of its expiration,
+// so that calls never wait for it. Call Client.Close to stop it.
+func WithBackgroundTokenRefresh() ClientOption {
+	return func(o *ClientOptions) {
+		o.backgroundTokenRefresh = true
+	}
+}
+
+// WithAuthenticator replaces the default IBM Cloud IAM authentication, e.g. with
+// NewCP4DAuthenticator, NewBearerTokenAuthenticator or NewNoAuthAuthenticator.
+// No API key is required when it is set.
+func WithAuthenticator(authenticator Authenticator) ClientOption {
+	return func(o *ClientOptions) {
+		o.authenticator = authenticator
+	}
+}
+
+// WithEmbeddingCache caches embeddings by model, truncation and text, so that EmbedDocuments
+// only sends the texts missing from the cache, e.g. NewLRUEmbeddingCache or NewDiskEmbeddingCache
+func WithEmbeddingCache(cache EmbeddingCache) ClientOption {
+	return func(o *ClientOptions) {
+		o.embeddingCache = cache
+	}
+}
+
+// WithRateLimit shapes the traffic of every call made by the client, e.g. with
+// WithRequestsPerSecond, WithMaxConcurrentRequests and WithTokensPerMinute
+func WithRateLimit(options ...RateLimitOption) ClientOption {
+	return func(o *ClientOptions) {
+		o.rateLimitOptions = append(o.rateLimitOptions, options...)
+	}
+}
+
+// WithUsageRecorder reports the model, tokens, latency and stop reason of every request to the recorder,
+// e.g. a UsageAggregator. Calls add tags to their usage with ContextWithUsageTags.
+func WithUsageRecorder(recorder UsageRecorder) ClientOption {
+	return func(o *ClientOptions) {
+		o.usageRecorder = recorder
+	}
+}
+
+// WithTracerProvider traces the calls of the client and its IAM requests, with the attributes of the
+// OpenTelemetry gen_ai semantic conventions and an event per retry. Nothing is traced by default.
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return func(o *ClientOptions) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records the duration, token usage and time to first chunk of the calls of the client
+// as OpenTelemetry metrics. Nothing is recorded by default.
+func WithMeterProvider(provider metric.MeterProvider) ClientOption {
+	return func(o *ClientOptions) {
+		o.meterProvider = provider
+	}
+}
+
+// WithLogger logs every request at debug level: its method, endpoint and payload, then its status,
+// duration and error, and each retry attempt. The API key and bearer tokens are always redacted.
+// Nothing is logged by default.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(o *ClientOptions) {
+		o.logger = logger
+	}
+}
+
+// WithRedactedPrompts replaces the prompts, inputs and messages of the payloads logged by WithLogger with [REDACTED]
+func WithRedactedPrompts() ClientOption {
+	return func(o *ClientOptions) {
+		o.redactPrompts = true
 	}
 }
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	CP4DAuthorizePath string = "/icp4d-api/v1/authorize"
+)
+
+// CP4DAuthenticator authenticates against Cloud Pak for Data with a username and either a password or an API key
+type CP4DAuthenticator struct {
+	host     string
+	username string
+	password string
+	apiKey   string
+}
+
+type cp4dAuthorizeRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+}
+
+type cp4dAuthorizeResponse struct {
+	Token string `json:"token"`
+}
+
+// NewCP4DAuthenticator creates an authenticator for the Cloud Pak for Data cluster at host,
+// e.g. "cpd-cpd-instance.apps.example.com", using a username and password
+func NewCP4DAuthenticator(host, username, password string) *CP4DAuthenticator {
+	return &CP4DAuthenticator{host: host, username: username, password: password}
+}
+
+// NewCP4DAPIKeyAuthenticator is like NewCP4DAuthenticator but uses a username and API key
+func NewCP4DAPIKeyAuthenticator(host, username, apiKey string) *CP4DAuthenticator {
+	return &CP4DAuthenticator{host: host, username: username, apiKey: apiKey}
+}
+
+func (a *CP4DAuthenticator) Authenticate(ctx context.Context, client Doer) (IAMToken, error) {
+	payload, err := json.Marshal(cp4dAuthorizeRequest{
+		Username: a.username,
+		Password: a.password,
+		APIKey:   a.apiKey,
+	})
+	if err != nil {
+		return IAMToken{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, buildURL(a.host, CP4DAuthorizePath, nil), bytes.NewReader(payload))
+	if err != nil {
+		return IAMToken{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return IAMToken{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return IAMToken{}, newAPIError(resp)
+	}
+	defer resp.Body.Close()
+
+	var authRes cp4dAuthorizeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authRes); err != nil {
+		return IAMToken{}, err
+	}
+
+	if authRes.Token == "" {
+		return IAMToken{}, errors.New("no token received from Cloud Pak for Data")
+	}
+
+	return NewIAMToken(authRes.Token, jwtExpiration(authRes.Token)), nil
+}
+
+// jwtExpiration reads the expiration claim of a JWT without verifying it.
+// Returns the zero time if the token carries none, in which case it is renewed when rejected.
+func jwtExpiration(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	claims, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var payload struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(claims, &payload); err != nil || payload.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(payload.Exp, 0)
+}
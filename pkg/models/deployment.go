@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+const (
+	DeploymentsEndpoint string = "/ml/v1/deployments"
+)
+
+// DeploymentPayload is the body of a generate request against a deployment.
+// The model, and the project or space, are those of the deployment.
+type DeploymentPayload struct {
+	Prompt     string                `json:"input,omitempty"`
+	Parameters *DeploymentParameters `json:"parameters,omitempty"`
+}
+
+// DeploymentParameters are the generation parameters of a deployment request,
+// along with the variables filling in the placeholders of a prompt template deployment
+type DeploymentParameters struct {
+	*GenerateOptions
+	PromptVariables map[string]string `json:"prompt_variables,omitempty"`
+}
+
+// deploymentEndpoint returns the endpoint of the deployment with the given ID or serving name,
+// escaped so that it stays a single path segment
+func deploymentEndpoint(deploymentID, path string) string {
+	return DeploymentsEndpoint + "/" + url.PathEscape(deploymentID) + path
+}
+
+func newDeploymentPayload(deploymentID, prompt string, options []GenerateOption) (DeploymentPayload, error) {
+	if deploymentID == "" {
+		return DeploymentPayload{}, errors.New("deployment ID cannot be empty")
+	}
+	if deploymentID == "." || deploymentID == ".." {
+		return DeploymentPayload{}, errors.New("invalid deployment ID")
+	}
+
+	opts := &GenerateOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	if prompt == "" && len(opts.promptVariables) == 0 {
+		return DeploymentPayload{}, errors.New("prompt cannot be empty without prompt variables")
+	}
+
+	return DeploymentPayload{
+		Prompt: prompt,
+		Parameters: &DeploymentParameters{
+			GenerateOptions: opts,
+			PromptVariables: opts.promptVariables,
+		},
+	}, nil
+}
+
+// GenerateTextDeployment generates completion text with a deployed model or prompt template.
+// The prompt may be empty for prompt template deployments, whose placeholders are filled in with WithPromptVariables.
+func (m *Client) GenerateTextDeployment(deploymentID, prompt string, options ...GenerateOption) (GenerateTextResult, error) {
+	return m.GenerateTextDeploymentContext(context.Background(), deploymentID, prompt, options...)
+}
+
+// GenerateTextDeploymentContext is like GenerateTextDeployment but binds the request to ctx
+func (m *Client) GenerateTextDeploymentContext(ctx context.Context, deploymentID, prompt string, options ...GenerateOption) (GenerateTextResult, error) {
+	payload, err := newDeploymentPayload(deploymentID, prompt, options)
+	if err != nil {
+		return GenerateTextResult{}, err
+	}
+
+	return m.generateText(ctx, deploymentEndpoint(deploymentID, "/text/generation"), payload, Usage{Operation: OperationTextCompletion, DeploymentID: deploymentID})
+}
+
+// OpenTextDeploymentStream starts a streamed generation with a deployed model or prompt template.
+// The stream must be closed once done with.
+func (m *Client) OpenTextDeploymentStream(deploymentID, prompt string, options ...GenerateOption) (*TextStream, error) {
+	return m.OpenTextDeploymentStreamContext(context.Background(), deploymentID, prompt, options...)
+}
+
+// OpenTextDeploymentStreamContext is like OpenTextDeploymentStream but binds the stream to ctx
+func (m *Client) OpenTextDeploymentStreamContext(ctx context.Context, deploymentID, prompt string, options ...GenerateOption) (*TextStream, error) {
+	payload, err := newDeploymentPayload(deploymentID, prompt, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.openTextStream(ctx, deploymentEndpoint(deploymentID, "/text/generation_stream"), payload, Usage{Operation: OperationTextCompletion, DeploymentID: deploymentID})
+}
+
+// GenerateTextDeploymentStream generates completion text channel (stream) with a deployed model or prompt template
+func (m *Client) GenerateTextDeploymentStream(deploymentID, prompt string, options ...GenerateOption) (<-chan GenerateTextResult, error) {
+	return m.GenerateTextDeploymentStreamContext(context.Background(), deploymentID, prompt, options...)
+}
+
+// GenerateTextDeploymentStreamContext is like GenerateTextDeploymentStream but binds the stream to ctx.
+// Cancelling ctx aborts the request and closes the channel.
+func (m *Client) GenerateTextDeploymentStreamContext(ctx context.Context, deploymentID, prompt string, options ...GenerateOption) (<-chan GenerateTextResult, error) {
+	stream, err := m.OpenTextDeploymentStreamContext(ctx, deploymentID, prompt, options...)
+	return textStreamChan(ctx, stream, err)
+}
@@ -1,19 +1,24 @@
 package models
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
 const (
 	EmbeddingEndpoint string = "/ml/v1/text/embeddings"
+
+	DefaultEmbeddingBatchSize = 1000 // Most texts watsonx embeds in one request
 )
 
 type EmbeddingPayload struct {
-	ProjectID  string            `json:"project_id"`
+	ProjectID  string            `json:"project_id,omitempty"`
+	SpaceID    string            `json:"space_id,omitempty"`
 	Model      string            `json:"model_id"`
 	Inputs     []string          `json:"inputs"`
 	Parameters *EmbeddingOptions `json:"parameters,omitempty"`
@@ -27,8 +32,9 @@ type EmbeddingResponse struct {
 }
 
 type EmbeddingResult struct {
-	Embedding []float64 `json:"embedding"`
-	Input     string    `json:"input,omitempty"`
+	Embedding   []float64 `json:"embedding"`
+	Embedding32 []float32 `json:"-"` // Set instead of Embedding with WithEmbeddingFloat32
+	Input       string    `json:"input,omitempty"`
 }
 
 type embeddingResponse struct {
@@ -37,10 +43,22 @@ type embeddingResponse struct {
 	EmbeddingResponse
 }
 
+// embeddingResponse32 decodes the embeddings straight into float32
+type embeddingResponse32 struct {
+	embeddingResponse
+	Results []struct {
+		Embedding []float32 `json:"embedding"`
+		Input     string    `json:"input,omitempty"`
+	} `json:"results"`
+}
+
 // EmbedDocuments embeds the given texts using the specified model.
 func (m *Client) EmbedDocuments(model string, texts []string, options ...EmbeddingOption) (EmbeddingResponse, error) {
-	m.CheckAndRefreshToken()
+	return m.EmbedDocumentsContext(context.Background(), model, texts, options...)
+}
 
+// EmbedDocumentsContext is like EmbedDocuments but binds the request to ctx.
+func (m *Client) EmbedDocumentsContext(ctx context.Context, model string, texts []string, options ...EmbeddingOption) (EmbeddingResponse, error) {
 	opts := &EmbeddingOptions{}
 	for _, opt := range options {
 		if opt != nil {
@@ -48,14 +66,33 @@ func (m *Client) EmbedDocuments(model string, texts []string, options ...Embeddi
 		}
 	}
 
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return EmbeddingResponse{}, err
+	}
+
 	payload := EmbeddingPayload{
-		ProjectID:  m.projectID,
+		ProjectID:  projectID,
+		SpaceID:    spaceID,
 		Model:      model,
 		Inputs:     texts,
 		Parameters: opts,
 	}
 
-	response, err := m.generateEmbeddingRequest(payload)
+	if m.embeddingCache != nil {
+		response, err := m.embedDocumentsCached(ctx, payload)
+		if err != nil {
+			return EmbeddingResponse{}, err
+		}
+
+		if len(response.Results) == 0 {
+			return EmbeddingResponse{}, errors.New("no result received")
+		}
+
+		return response, nil
+	}
+
+	response, err := m.generateEmbeddingRequest(ctx, payload)
 	if err != nil {
 		return EmbeddingResponse{}, err
 	}
@@ -69,32 +106,116 @@ func (m *Client) EmbedDocuments(model string, texts []string, options ...Embeddi
 
 // EmbedQuery embeds the given text using the specified model.
 func (m *Client) EmbedQuery(model string, text string, options ...EmbeddingOption) (EmbeddingResponse, error) {
-	return m.EmbedDocuments(model, []string{text}, options...)
+	return m.EmbedQueryContext(context.Background(), model, text, options...)
+}
+
+// EmbedQueryContext is like EmbedQuery but binds the request to ctx.
+func (m *Client) EmbedQueryContext(ctx context.Context, model string, text string, options ...EmbeddingOption) (EmbeddingResponse, error) {
+	return m.EmbedDocumentsContext(ctx, model, []string{text}, options...)
+}
+
+// EmbedDocumentsBatched embeds any number of texts, sending them in batches of WithEmbeddingBatchSize texts
+// (DefaultEmbeddingBatchSize by default) with at most WithEmbeddingConcurrency requests at once.
+// The results are in the order of the texts and InputTokenCount sums all batches.
+// Failed batches leave nil embeddings and are reported in the returned error: the Index of each of its
+// BatchErrors is that of a batch, whose texts start at Index times the batch size.
+func (m *Client) EmbedDocumentsBatched(model string, texts []string, options ...EmbeddingOption) (EmbeddingResponse, error) {
+	return m.EmbedDocumentsBatchedContext(context.Background(), model, texts, options...)
+}
+
+// EmbedDocumentsBatchedContext is like EmbedDocumentsBatched but binds the requests to ctx.
+// Batches not sent when ctx is done fail with the context's error.
+func (m *Client) EmbedDocumentsBatchedContext(ctx context.Context, model string, texts []string, options ...EmbeddingOption) (EmbeddingResponse, error) {
+	if len(texts) == 0 {
+		return EmbeddingResponse{}, errors.New("texts cannot be empty")
+	}
+
+	opts := &EmbeddingOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	batchSize := int(opts.batchSize)
+	if batchSize == 0 {
+		batchSize = DefaultEmbeddingBatchSize
+	}
+	batches := (len(texts) + batchSize - 1) / batchSize
+
+	merged := EmbeddingResponse{
+		Model:   model,
+		Results: make([]EmbeddingResult, len(texts)),
+	}
+	var mu sync.Mutex
+
+	err := runBatch(ctx, batches, int(opts.concurrency), func(ctx context.Context, batch int) error {
+		start := batch * batchSize
+		end := min(start+batchSize, len(texts))
+
+		response, err := m.EmbedDocumentsContext(ctx, model, texts[start:end], options...)
+		if err != nil {
+			return err
+		}
+		if len(response.Results) != end-start {
+			return fmt.Errorf("expected %d embeddings, but received %d", end-start, len(response.Results))
+		}
+
+		copy(merged.Results[start:end], response.Results)
+
+		mu.Lock()
+		defer mu.Unlock()
+		merged.InputTokenCount += response.InputTokenCount
+		if response.CreatedAt.After(merged.CreatedAt) {
+			merged.CreatedAt = response.CreatedAt
+		}
+
+		return nil
+	})
+
+	return merged, err
 }
 
-// generateEmbeddingRequest sends a request to the embedding endpoint with the given payload.
+// generateEmbeddingRequest sends a request to the embedding endpoint with the given payload and records its usage.
 // return the response from the server if and only if the request is successful, code 200.
-func (m *Client) generateEmbeddingRequest(payload EmbeddingPayload) (embeddingResponse, error) {
-	embeddingUrl := m.generateUrlFromEndpoint(EmbeddingEndpoint)
+func (m *Client) generateEmbeddingRequest(ctx context.Context, payload EmbeddingPayload) (embeddingResponse, error) {
+	usage := Usage{Operation: OperationEmbeddings, Model: payload.Model}
+	ctx, call := m.startCall(ctx, usage)
+	response, err := m.sendEmbeddingRequest(ctx, payload)
 
-	payloadJSON, err := json.Marshal(payload)
+	usage.InputTokens = response.InputTokenCount
+	usage.Err = err
+	call.end(usage)
+
+	return response, err
+}
+
+func (m *Client) sendEmbeddingRequest(ctx context.Context, payload EmbeddingPayload) (embeddingResponse, error) {
+	req, err := m.newJSONRequest(ctx, http.MethodPost, EmbeddingEndpoint, payload)
 	if err != nil {
 		return embeddingResponse{}, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, embeddingUrl, bytes.NewBuffer(payloadJSON))
+	res, err := m.doRequest(req)
 	if err != nil {
 		return embeddingResponse{}, err
 	}
+	defer res.Body.Close()
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.token.value)
+	if payload.Parameters != nil && payload.Parameters.float32 {
+		var embeddingRes embeddingResponse32
 
-	res, err := m.httpClient.DoWithRetry(req)
-	if err != nil {
-		return embeddingResponse{}, err
+		if err := json.NewDecoder(res.Body).Decode(&embeddingRes); err != nil {
+			return embeddingResponse{}, err
+		}
+
+		embeddingRes.embeddingResponse.Results = make([]EmbeddingResult, len(embeddingRes.Results))
+		for i, result := range embeddingRes.Results {
+			embeddingRes.embeddingResponse.Results[i] = EmbeddingResult{Embedding32: result.Embedding, Input: result.Input}
+		}
+
+		return embeddingRes.embeddingResponse, nil
 	}
-	defer res.Body.Close()
 
 	var embeddingRes embeddingResponse
 
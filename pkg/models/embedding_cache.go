@@ -0,0 +1,284 @@
+package models
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// EmbeddingCache stores embeddings across EmbedDocuments calls, see WithEmbeddingCache.
+// Implementations must be safe for concurrent use. A cache that fails to read or write
+// should report a miss rather than fail the call.
+type EmbeddingCache interface {
+	Get(key EmbeddingCacheKey) (EmbeddingResult, bool)
+	Put(key EmbeddingCacheKey, result EmbeddingResult)
+}
+
+// EmbeddingCacheKey identifies the embedding of a text by a model
+type EmbeddingCacheKey struct {
+	Model               string
+	TruncateInputTokens uint // 0 when the input is not truncated
+	TextHash            [sha256.Size]byte
+}
+
+func NewEmbeddingCacheKey(model string, truncateInputTokens uint, text string) EmbeddingCacheKey {
+	return EmbeddingCacheKey{
+		Model:               model,
+		TruncateInputTokens: truncateInputTokens,
+		TextHash:            sha256.Sum256([]byte(text)),
+	}
+}
+
+func (k EmbeddingCacheKey) String() string {
+	return fmt.Sprintf("%s|%d|%x", k.Model, k.TruncateInputTokens, k.TextHash)
+}
+
+// embedDocumentsCached embeds the texts of the payload that are missing from the cache, once per distinct text,
+// and merges them with the cached ones in the order of the texts
+func (m *Client) embedDocumentsCached(ctx context.Context, payload EmbeddingPayload) (EmbeddingResponse, error) {
+	opts := payload.Parameters
+
+	var truncateInputTokens uint
+	if opts.TruncateInputTokens != nil {
+		truncateInputTokens = *opts.TruncateInputTokens
+	}
+
+	type miss struct {
+		key     EmbeddingCacheKey
+		indexes []int
+	}
+
+	results := make([]EmbeddingResult, len(payload.Inputs))
+	misses := map[string]*miss{}
+	var missTexts []string
+
+	for i, text := range payload.Inputs {
+		key := NewEmbeddingCacheKey(payload.Model, truncateInputTokens, text)
+
+		if result, ok := m.embeddingCache.Get(key); ok {
+			results[i] = opts.cachedResult(result, text)
+			continue
+		}
+
+		if misses[text] == nil {
+			misses[text] = &miss{key: key}
+			missTexts = append(missTexts, text)
+		}
+		misses[text].indexes = append(misses[text].indexes, i)
+	}
+
+	response := EmbeddingResponse{Model: payload.Model}
+
+	if len(missTexts) > 0 {
+		payload.Inputs = missTexts
+
+		embeddingRes, err := m.generateEmbeddingRequest(ctx, payload)
+		if err != nil {
+			return EmbeddingResponse{}, err
+		}
+		if len(embeddingRes.Results) != len(missTexts) {
+			return EmbeddingResponse{}, fmt.Errorf("expected %d embeddings, but received %d", len(missTexts), len(embeddingRes.Results))
+		}
+
+		response = embeddingRes.EmbeddingResponse
+
+		for j, text := range missTexts {
+			result := embeddingRes.Results[j]
+			// copied so that callers may modify their results, e.g. with Normalize
+			m.embeddingCache.Put(misses[text].key, EmbeddingResult{Embedding: slices.Clone(result.Embedding), Embedding32: slices.Clone(result.Embedding32)})
+
+			for k, i := range misses[text].indexes {
+				results[i] = result
+				if k > 0 {
+					// repeated texts get their own copy too
+					results[i].Embedding = slices.Clone(result.Embedding)
+					results[i].Embedding32 = slices.Clone(result.Embedding32)
+				}
+			}
+		}
+	}
+
+	response.Results = results
+
+	return response, nil
+}
+
+// cachedResult copies a cached result to the representation and return options of the call
+func (ep *EmbeddingOptions) cachedResult(result EmbeddingResult, text string) EmbeddingResult {
+	if ep.float32 {
+		result = EmbeddingResult{Embedding32: slices.Clone(result.Float32())}
+	} else {
+		result = EmbeddingResult{Embedding: slices.Clone(result.Float64())}
+	}
+
+	if ep.ReturnOptions != nil && ep.ReturnOptions.InputText {
+		result.Input = text
+	}
+
+	return result
+}
+
+// LRUEmbeddingCache is an in-memory EmbeddingCache evicting the least recently used embeddings
+type LRUEmbeddingCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // of *lruEntry, most recently used first
+	entries  map[EmbeddingCacheKey]*list.Element
+}
+
+type lruEntry struct {
+	key    EmbeddingCacheKey
+	result EmbeddingResult
+}
+
+// NewLRUEmbeddingCache creates an in-memory cache holding up to capacity embeddings
+func NewLRUEmbeddingCache(capacity int) *LRUEmbeddingCache {
+	return &LRUEmbeddingCache{
+		capacity: max(capacity, 1),
+		order:    list.New(),
+		entries:  map[EmbeddingCacheKey]*list.Element{},
+	}
+}
+
+func (c *LRUEmbeddingCache) Get(key EmbeddingCacheKey) (EmbeddingResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return EmbeddingResult{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).result, true
+}
+
+func (c *LRUEmbeddingCache) Put(key EmbeddingCacheKey, result EmbeddingResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).result = result
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key, result})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached embeddings
+func (c *LRUEmbeddingCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// DiskEmbeddingCache is an EmbeddingCache storing each embedding in a file of a directory,
+// so that it persists across runs and processes
+type DiskEmbeddingCache struct {
+	dir string
+}
+
+const (
+	diskEmbeddingFloat32 byte = 4
+	diskEmbeddingFloat64 byte = 8
+)
+
+// NewDiskEmbeddingCache creates a cache in dir, creating the directory if needed
+func NewDiskEmbeddingCache(dir string) (*DiskEmbeddingCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &DiskEmbeddingCache{dir: dir}, nil
+}
+
+// path returns the file of the key, named after the hash of the key as models contain slashes
+func (c *DiskEmbeddingCache) path(key EmbeddingCacheKey) string {
+	hash := sha256.Sum256([]byte(key.String()))
+	name := hex.EncodeToString(hash[:])
+
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+func (c *DiskEmbeddingCache) Get(key EmbeddingCacheKey) (EmbeddingResult, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil || len(data) == 0 {
+		return EmbeddingResult{}, false
+	}
+
+	size, data := data[0], data[1:]
+
+	switch {
+	case size == diskEmbeddingFloat32 && len(data)%4 == 0:
+		embedding := make([]float32, len(data)/4)
+		for i := range embedding {
+			embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+		}
+		return EmbeddingResult{Embedding32: embedding}, true
+
+	case size == diskEmbeddingFloat64 && len(data)%8 == 0:
+		embedding := make([]float64, len(data)/8)
+		for i := range embedding {
+			embedding[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[i*8:]))
+		}
+		return EmbeddingResult{Embedding: embedding}, true
+	}
+
+	return EmbeddingResult{}, false
+}
+
+func (c *DiskEmbeddingCache) Put(key EmbeddingCacheKey, result EmbeddingResult) {
+	var data []byte
+
+	if result.Embedding == nil && result.Embedding32 != nil {
+		data = make([]byte, 1, 1+len(result.Embedding32)*4)
+		data[0] = diskEmbeddingFloat32
+		for _, x := range result.Embedding32 {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(x))
+		}
+	} else {
+		data = make([]byte, 1, 1+len(result.Embedding)*8)
+		data[0] = diskEmbeddingFloat64
+		for _, x := range result.Embedding {
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(x))
+		}
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	// write then rename, so that concurrent readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+
+	os.Rename(tmp.Name(), path)
+}
@@ -7,6 +7,11 @@ type EmbeddingOption func(*EmbeddingOptions)
 type EmbeddingOptions struct {
 	TruncateInputTokens *uint                   `json:"truncate_input_tokens,omitempty"`
 	ReturnOptions       *EmbeddingReturnOptions `json:"return_options,omitempty"`
+
+	batchSize   uint // Only used by EmbedDocumentsBatched
+	concurrency uint // Only used by EmbedDocumentsBatched
+	float32     bool // Decode into EmbeddingResult.Embedding32
+	scope       callScope
 }
 
 type EmbeddingReturnOptions struct {
@@ -25,6 +30,44 @@ func WithEmbeddingReturnOptions(inputText bool) EmbeddingOption {
 	}
 }
 
+// WithEmbeddingBatchSize sets how many texts EmbedDocumentsBatched sends per request
+func WithEmbeddingBatchSize(batchSize uint) EmbeddingOption {
+	return func(opts *EmbeddingOptions) {
+		opts.batchSize = batchSize
+	}
+}
+
+// WithEmbeddingConcurrency limits how many requests EmbedDocumentsBatched sends at once
+func WithEmbeddingConcurrency(concurrency uint) EmbeddingOption {
+	return func(opts *EmbeddingOptions) {
+		opts.concurrency = concurrency
+	}
+}
+
+// WithEmbeddingFloat32 decodes the embeddings into EmbeddingResult.Embedding32 instead of Embedding,
+// halving their memory
+func WithEmbeddingFloat32() EmbeddingOption {
+	return func(opts *EmbeddingOptions) {
+		opts.float32 = true
+	}
+}
+
+// WithEmbeddingProjectID runs the call in the given project instead of the client's project or space;
+// it cannot be combined with WithEmbeddingSpaceID
+func WithEmbeddingProjectID(projectID WatsonxProjectID) EmbeddingOption {
+	return func(opts *EmbeddingOptions) {
+		opts.scope.projectID = projectID
+	}
+}
+
+// WithEmbeddingSpaceID runs the call in the given deployment space instead of the client's project or space;
+// it cannot be combined with WithEmbeddingProjectID
+func WithEmbeddingSpaceID(spaceID WatsonxSpaceID) EmbeddingOption {
+	return func(opts *EmbeddingOptions) {
+		opts.scope.spaceID = spaceID
+	}
+}
+
 func (ep *EmbeddingOptions) String() string {
 	return fmt.Sprintf(
 		"truncateInputTokens: %v\n"+
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize bounds how much of an error response body is read
+const maxErrorBodySize = 1024 * 1024
+
+// APIError is returned when watsonx or IAM answers with an unsuccessful status code.
+// Use errors.As to inspect it:
+//
+//	var apiErr *APIError
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests { ... }
+type APIError struct {
+	StatusCode int
+	Status     string
+	Errors     []APIErrorDetail
+	Trace      string      // Trace ID to share with IBM support
+	Header     http.Header // Headers of the response, e.g. Retry-After
+	Body       []byte      // Raw body of the response
+}
+
+type APIErrorDetail struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	MoreInfo string `json:"more_info,omitempty"`
+}
+
+// apiErrorResponse is the error body of the watsonx API
+type apiErrorResponse struct {
+	Errors     []APIErrorDetail `json:"errors"`
+	Trace      string           `json:"trace"`
+	StatusCode int              `json:"status_code"`
+}
+
+// iamErrorResponse is the error body of the IAM token endpoint
+type iamErrorResponse struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+	Context      struct {
+		RequestID string `json:"requestId"`
+	} `json:"context"`
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Status)
+
+	for _, detail := range e.Errors {
+		fmt.Fprintf(&sb, "; %s: %s", detail.Code, detail.Message)
+	}
+
+	if e.Trace != "" {
+		fmt.Fprintf(&sb, " (trace: %s)", e.Trace)
+	}
+
+	return sb.String()
+}
+
+// HasCode reports whether any of the error details carries the given code
+func (e *APIError) HasCode(code string) bool {
+	for _, detail := range e.Errors {
+		if detail.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// newAPIError builds an APIError from an unsuccessful response, consuming and closing its body
+func newAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+	}
+	if apiErr.Status == "" {
+		apiErr.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return apiErr
+	}
+	apiErr.Body = body
+
+	apiErr.parseBody(body)
+
+	return apiErr
+}
+
+// parseBody fills the error details from either a watsonx or an IAM error body
+func (e *APIError) parseBody(body []byte) {
+	var wxErr apiErrorResponse
+	if err := json.Unmarshal(body, &wxErr); err == nil && (len(wxErr.Errors) > 0 || wxErr.Trace != "") {
+		e.Errors = wxErr.Errors
+		e.Trace = wxErr.Trace
+		return
+	}
+
+	var iamErr iamErrorResponse
+	if err := json.Unmarshal(body, &iamErr); err == nil && iamErr.ErrorCode != "" {
+		e.Errors = []APIErrorDetail{{Code: iamErr.ErrorCode, Message: iamErr.ErrorMessage}}
+		e.Trace = iamErr.Context.RequestID
+	}
+}
+
+// newStreamAPIError builds an APIError from the data of an error event sent mid-stream
+func newStreamAPIError(data []byte) *APIError {
+	apiErr := &APIError{Body: data}
+	apiErr.parseBody(data)
+
+	var wxErr apiErrorResponse
+	if err := json.Unmarshal(data, &wxErr); err == nil && wxErr.StatusCode != 0 {
+		apiErr.StatusCode = wxErr.StatusCode
+		apiErr.Status = fmt.Sprintf("%d %s", wxErr.StatusCode, http.StatusText(wxErr.StatusCode))
+	} else {
+		apiErr.Status = "stream error"
+	}
+
+	return apiErr
+}
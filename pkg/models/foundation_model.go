@@ -0,0 +1,302 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	FoundationModelSpecsEndpoint string = "/ml/v1/foundation_model_specs"
+)
+
+type ModelFunction = string
+
+const (
+	ModelFunctionTextGeneration ModelFunction = "text_generation"
+	ModelFunctionTextChat       ModelFunction = "text_chat"
+	ModelFunctionEmbedding      ModelFunction = "embedding"
+	ModelFunctionRerank         ModelFunction = "rerank"
+)
+
+type ModelLifecycleID = string
+
+const (
+	ModelAvailable   ModelLifecycleID = "available"
+	ModelDeprecated  ModelLifecycleID = "deprecated"
+	ModelConstricted ModelLifecycleID = "constricted" // Only available to existing deployments
+	ModelWithdrawn   ModelLifecycleID = "withdrawn"
+)
+
+var (
+	ErrModelNotFound  = errors.New("foundation model not found")
+	ErrModelWithdrawn = errors.New("foundation model withdrawn")
+)
+
+// ModelDate is a calendar date of the model catalogue, e.g. "2024-07-23"
+type ModelDate struct {
+	time.Time
+}
+
+func (d *ModelDate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, s)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+	return nil
+}
+
+func (d ModelDate) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return json.Marshal("")
+	}
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+type FoundationModelSpec struct {
+	ModelID            string                 `json:"model_id"`
+	Label              string                 `json:"label"`
+	Provider           string                 `json:"provider"`
+	Source             string                 `json:"source"`
+	ShortDescription   string                 `json:"short_description"`
+	LongDescription    string                 `json:"long_description"`
+	InputTier          string                 `json:"input_tier"`
+	OutputTier         string                 `json:"output_tier"`
+	NumberParams       string                 `json:"number_params"`
+	Functions          []ModelFunctionSpec    `json:"functions"`
+	TaskIDs            []string               `json:"task_ids"`
+	Tasks              []ModelTask            `json:"tasks"`
+	ModelLimits        ModelLimits            `json:"model_limits"`
+	Lifecycle          []ModelLifecycle       `json:"lifecycle"`
+	TechPreview        bool                   `json:"tech_preview"`
+	Versions           []ModelVersion         `json:"versions"`
+	SupportedLanguages []string               `json:"supported_languages"`
+	Limits             map[string]ModelLimits `json:"limits"` // Limits per plan
+}
+
+type ModelFunctionSpec struct {
+	ID ModelFunction `json:"id"`
+}
+
+type ModelTask struct {
+	ID      string         `json:"id"`
+	Ratings map[string]int `json:"ratings,omitempty"`
+	Tags    []string       `json:"tags,omitempty"`
+}
+
+type ModelLimits struct {
+	MaxSequenceLength      int    `json:"max_sequence_length,omitempty"`
+	MaxOutputTokens        int    `json:"max_output_tokens,omitempty"`
+	TrainingDataMaxRecords int    `json:"training_data_max_records,omitempty"`
+	CallTime               string `json:"call_time,omitempty"`
+}
+
+type ModelLifecycle struct {
+	ID                  ModelLifecycleID `json:"id"`
+	Label               string           `json:"label,omitempty"`
+	StartDate           ModelDate        `json:"start_date"`
+	AlternativeModelIDs []string         `json:"alternative_model_ids,omitempty"`
+}
+
+type ModelVersion struct {
+	Version       string    `json:"version"`
+	AvailableDate ModelDate `json:"available_date"`
+}
+
+// LifecycleAt returns the lifecycle stage of the model at the given time, or false before the model is available
+func (s FoundationModelSpec) LifecycleAt(t time.Time) (ModelLifecycle, bool) {
+	var stage ModelLifecycle
+	found := false
+
+	for _, lifecycle := range s.Lifecycle {
+		if lifecycle.StartDate.After(t) {
+			continue
+		}
+		if !found || !lifecycle.StartDate.Before(stage.StartDate.Time) {
+			stage, found = lifecycle, true
+		}
+	}
+
+	return stage, found
+}
+
+// Deprecation returns the deprecation stage of the model, which may lie in the future
+func (s FoundationModelSpec) Deprecation() (ModelLifecycle, bool) {
+	return s.lifecycleStage(ModelDeprecated)
+}
+
+// Withdrawal returns the withdrawal stage of the model, which may lie in the future
+func (s FoundationModelSpec) Withdrawal() (ModelLifecycle, bool) {
+	return s.lifecycleStage(ModelWithdrawn)
+}
+
+func (s FoundationModelSpec) lifecycleStage(id ModelLifecycleID) (ModelLifecycle, bool) {
+	for _, lifecycle := range s.Lifecycle {
+		if lifecycle.ID == id {
+			return lifecycle, true
+		}
+	}
+	return ModelLifecycle{}, false
+}
+
+// HasFunction reports whether the model supports the function, e.g. ModelFunctionTextChat
+func (s FoundationModelSpec) HasFunction(function ModelFunction) bool {
+	for _, f := range s.Functions {
+		if f.ID == function {
+			return true
+		}
+	}
+	return false
+}
+
+type FoundationModelList struct {
+	TotalCount int                   `json:"total_count"`
+	Limit      int                   `json:"limit"`
+	First      *PaginationLink       `json:"first,omitempty"`
+	Next       *PaginationLink       `json:"next,omitempty"`
+	Resources  []FoundationModelSpec `json:"resources"`
+}
+
+type PaginationLink struct {
+	Href string `json:"href"`
+}
+
+// NextStart returns the start token of the next page, or "" on the last page
+func (l FoundationModelList) NextStart() string {
+	if l.Next == nil {
+		return ""
+	}
+
+	u, err := url.Parse(l.Next.Href)
+	if err != nil {
+		return ""
+	}
+
+	return u.Query().Get("start")
+}
+
+// ListFoundationModels lists a page of the foundation models available to the client
+func (m *Client) ListFoundationModels(options ...FoundationModelOption) (FoundationModelList, error) {
+	return m.ListFoundationModelsContext(context.Background(), options...)
+}
+
+// ListFoundationModelsContext is like ListFoundationModels but binds the request to ctx
+func (m *Client) ListFoundationModelsContext(ctx context.Context, options ...FoundationModelOption) (FoundationModelList, error) {
+	opts := &FoundationModelOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	usage := Usage{Operation: OperationListModels}
+	ctx, call := m.startCall(ctx, usage)
+	list, err := m.listFoundationModelsRequest(ctx, opts)
+
+	usage.Err = err
+	call.end(usage)
+
+	return list, err
+}
+
+func (m *Client) listFoundationModelsRequest(ctx context.Context, opts *FoundationModelOptions) (FoundationModelList, error) {
+	req, err := m.newRequest(ctx, http.MethodGet, m.generateUrlWithParams(FoundationModelSpecsEndpoint, opts.params()), nil)
+	if err != nil {
+		return FoundationModelList{}, err
+	}
+
+	res, err := m.doRequest(req)
+	if err != nil {
+		return FoundationModelList{}, err
+	}
+	defer res.Body.Close()
+
+	var list FoundationModelList
+
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		return FoundationModelList{}, err
+	}
+
+	return list, nil
+}
+
+// ListAllFoundationModels lists the foundation models of every page
+func (m *Client) ListAllFoundationModels(options ...FoundationModelOption) ([]FoundationModelSpec, error) {
+	return m.ListAllFoundationModelsContext(context.Background(), options...)
+}
+
+// ListAllFoundationModelsContext is like ListAllFoundationModels but binds the requests to ctx
+func (m *Client) ListAllFoundationModelsContext(ctx context.Context, options ...FoundationModelOption) ([]FoundationModelSpec, error) {
+	var specs []FoundationModelSpec
+
+	start := ""
+	for {
+		pageOptions := options
+		if start != "" {
+			pageOptions = append(options[:len(options):len(options)], WithFoundationModelStart(start))
+		}
+
+		list, err := m.ListFoundationModelsContext(ctx, pageOptions...)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, list.Resources...)
+
+		next := list.NextStart()
+		if next == "" || next == start || len(list.Resources) == 0 {
+			return specs, nil
+		}
+		start = next
+	}
+}
+
+// ValidateModelID looks up a model before use. It returns ErrModelNotFound for unknown models
+// and ErrModelWithdrawn once the model is withdrawn; check Deprecation for upcoming withdrawals.
+func (m *Client) ValidateModelID(modelID string) (FoundationModelSpec, error) {
+	return m.ValidateModelIDContext(context.Background(), modelID)
+}
+
+// ValidateModelIDContext is like ValidateModelID but binds the request to ctx
+func (m *Client) ValidateModelIDContext(ctx context.Context, modelID string) (FoundationModelSpec, error) {
+	if strings.TrimSpace(modelID) == "" {
+		return FoundationModelSpec{}, errors.New("model ID cannot be empty")
+	}
+
+	specs, err := m.ListAllFoundationModelsContext(ctx, WithFoundationModelID(modelID))
+	if err != nil {
+		return FoundationModelSpec{}, err
+	}
+
+	for _, spec := range specs {
+		if spec.ModelID != modelID {
+			continue
+		}
+
+		if stage, ok := spec.LifecycleAt(time.Now()); ok && stage.ID == ModelWithdrawn {
+			return spec, fmt.Errorf("%w: %s on %s", ErrModelWithdrawn, modelID, stage.StartDate.Format(time.DateOnly))
+		}
+
+		return spec, nil
+	}
+
+	return FoundationModelSpec{}, fmt.Errorf("%w: %s", ErrModelNotFound, modelID)
+}
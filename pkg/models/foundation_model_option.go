@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type FoundationModelOption func(*FoundationModelOptions)
+
+type FoundationModelOptions struct {
+	// Filters in the watsonx syntax, e.g. "function_text_generation" or "!lifecycle_withdrawn"
+	Filters     []string
+	FilterOr    bool
+	TechPreview *bool
+	Limit       *int
+	Start       *string
+}
+
+func WithFoundationModelFunction(function ModelFunction) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.Filters = append(opts.Filters, "function_"+function)
+	}
+}
+
+func WithFoundationModelTask(task string) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.Filters = append(opts.Filters, "task_"+task)
+	}
+}
+
+func WithFoundationModelID(modelID string) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.Filters = append(opts.Filters, "modelid_"+modelID)
+	}
+}
+
+// WithFoundationModelFilters adds raw filters, e.g. "!lifecycle_withdrawn"
+func WithFoundationModelFilters(filters ...string) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.Filters = append(opts.Filters, filters...)
+	}
+}
+
+// WithFoundationModelFilterOr matches models satisfying any filter instead of all of them
+func WithFoundationModelFilterOr() FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.FilterOr = true
+	}
+}
+
+func WithFoundationModelTechPreview(techPreview bool) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.TechPreview = &techPreview
+	}
+}
+
+func WithFoundationModelLimit(limit int) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.Limit = &limit
+	}
+}
+
+// WithFoundationModelStart resumes listing at the token of FoundationModelList.NextStart
+func WithFoundationModelStart(start string) FoundationModelOption {
+	return func(opts *FoundationModelOptions) {
+		opts.Start = &start
+	}
+}
+
+// params returns the options as query parameters
+func (fo *FoundationModelOptions) params() url.Values {
+	params := url.Values{}
+
+	if len(fo.Filters) > 0 {
+		filters := strings.Join(fo.Filters, ",")
+		if fo.FilterOr {
+			filters += ":or"
+		}
+		params.Set("filters", filters)
+	}
+	if fo.TechPreview != nil {
+		params.Set("tech_preview", strconv.FormatBool(*fo.TechPreview))
+	}
+	if fo.Limit != nil {
+		params.Set("limit", strconv.Itoa(*fo.Limit))
+	}
+	if fo.Start != nil {
+		params.Set("start", *fo.Start)
+	}
+
+	return params
+}
+
+func (fo *FoundationModelOptions) String() string {
+	return fmt.Sprintf(
+		"filters: %v\n"+
+			"filterOr: %v\n"+
+			"techPreview: %v\n"+
+			"limit: %v\n"+
+			"start: %v",
+		fo.Filters,
+		fo.FilterOr,
+		fo.TechPreview,
+		fo.Limit,
+		fo.Start,
+	)
+}
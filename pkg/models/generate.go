@@ -1,13 +1,12 @@
 package models
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"io"
 	"net/http"
-	"strings"
+	"sync"
 )
 
 const (
@@ -37,7 +36,8 @@ type GenerateTextResult struct {
 }
 
 type GenerateTextPayload struct {
-	ProjectID  string           `json:"project_id"`
+	ProjectID  string           `json:"project_id,omitempty"`
+	SpaceID    string           `json:"space_id,omitempty"`
 	Model      string           `json:"model_id"`
 	Prompt     string           `json:"input"`
 	Parameters *GenerateOptions `json:"parameters,omitempty"`
@@ -51,8 +51,11 @@ type generateTextResponse struct {
 
 // GenerateText generates completion text based on a given prompt and parameters
 func (m *Client) GenerateText(model, prompt string, options ...GenerateOption) (GenerateTextResult, error) {
-	m.CheckAndRefreshToken()
+	return m.GenerateTextContext(context.Background(), model, prompt, options...)
+}
 
+// GenerateTextContext is like GenerateText but binds the request to ctx
+func (m *Client) GenerateTextContext(ctx context.Context, model, prompt string, options ...GenerateOption) (GenerateTextResult, error) {
 	if prompt == "" {
 		return GenerateTextResult{}, errors.New("prompt cannot be empty")
 	}
@@ -64,14 +67,81 @@ func (m *Client) GenerateText(model, prompt string, options ...GenerateOption) (
 		}
 	}
 
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return GenerateTextResult{}, err
+	}
+
 	payload := GenerateTextPayload{
-		ProjectID:  m.projectID,
+		ProjectID:  projectID,
+		SpaceID:    spaceID,
 		Model:      model,
 		Prompt:     prompt,
 		Parameters: opts,
 	}
 
-	response, err := m.generateTextRequest(payload)
+	return m.generateText(ctx, GenerateTextEndpoint, payload, Usage{Operation: OperationTextCompletion, Model: model})
+}
+
+// GenerateTextBatch generates completion texts for many prompts with at most WithBatchConcurrency
+// requests at once (DefaultBatchConcurrency by default), reporting each finished prompt to WithBatchProgress.
+// The results are in the order of the prompts. Failed prompts have an empty result and are reported
+// in the returned error, whose BatchErrors carry their indexes and errors, e.g. *APIError.
+func (m *Client) GenerateTextBatch(model string, prompts []string, options ...GenerateOption) ([]GenerateTextResult, error) {
+	return m.GenerateTextBatchContext(context.Background(), model, prompts, options...)
+}
+
+// GenerateTextBatchContext is like GenerateTextBatch but binds the requests to ctx.
+// Cancelling ctx aborts the requests in flight and fails the prompts not yet sent with the context's error.
+func (m *Client) GenerateTextBatchContext(ctx context.Context, model string, prompts []string, options ...GenerateOption) ([]GenerateTextResult, error) {
+	opts := &GenerateOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	// refresh the token once up front rather than racing the workers to it
+	if err := m.CheckAndRefreshTokenContext(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([]GenerateTextResult, len(prompts))
+	var mu sync.Mutex
+	completed := 0
+
+	err := runBatch(ctx, len(prompts), int(opts.batchConcurrency), func(ctx context.Context, i int) error {
+		result, err := m.GenerateTextContext(ctx, model, prompts[i], options...)
+		results[i] = result
+
+		if opts.batchProgress != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			completed++
+			opts.batchProgress(completed, len(prompts))
+		}
+
+		return err
+	})
+
+	return results, err
+}
+
+// generateText sends a generate request to endpoint and returns its first result, recording its usage
+func (m *Client) generateText(ctx context.Context, endpoint string, payload any, usage Usage) (GenerateTextResult, error) {
+	ctx, call := m.startCall(ctx, usage)
+	response, err := m.generateTextRequest(ctx, endpoint, payload)
+
+	usage.Err = err
+	for _, result := range response.Results {
+		usage.InputTokens += result.InputTokenCount
+		usage.GeneratedTokens += result.GeneratedTokenCount
+	}
+	if len(response.Results) > 0 {
+		usage.StopReason = response.Results[0].StopReason
+	}
+	call.end(usage)
+
 	if err != nil {
 		return GenerateTextResult{}, err
 	}
@@ -87,23 +157,13 @@ func (m *Client) GenerateText(model, prompt string, options ...GenerateOption) (
 
 // generateTextRequest sends the generate request and handles the response using the http package.
 // Returns error on non-2XX response
-func (m *Client) generateTextRequest(payload GenerateTextPayload) (generateTextResponse, error) {
-	textUrl := m.generateUrlFromEndpoint(GenerateTextEndpoint)
-
-	payloadJSON, err := json.Marshal(payload)
+func (m *Client) generateTextRequest(ctx context.Context, endpoint string, payload any) (generateTextResponse, error) {
+	req, err := m.newJSONRequest(ctx, http.MethodPost, endpoint, payload)
 	if err != nil {
 		return generateTextResponse{}, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, textUrl, bytes.NewBuffer(payloadJSON))
-	if err != nil {
-		return generateTextResponse{}, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+m.token.value)
-
-	res, err := m.httpClient.DoWithRetry(req)
+	res, err := m.doRequest(req)
 	if err != nil {
 		return generateTextResponse{}, err
 	}
@@ -118,96 +178,159 @@ func (m *Client) generateTextRequest(payload GenerateTextPayload) (generateTextR
 	return generateRes, nil
 }
 
-// GenerateTextStream generates completion text channel (stream) based on a given prompt and parameters
-func (m *Client) GenerateTextStream(model, prompt string, options ...GenerateOption) (<-chan GenerateTextResult, error) {
-	dataChan := make(chan GenerateTextResult)
+// TextStream iterates over the results of a streamed generation.
+//
+//	for stream.Next() {
+//		result := stream.Current()
+//	}
+//	if err := stream.Err(); err != nil { ... }
+//
+// Err distinguishes a normal finish from a failure: it is nil once the model stopped on its own
+// (e.g. eos_token or max_tokens), an *APIError for watsonx errors, and io.ErrUnexpectedEOF when
+// the connection dropped before the generation finished.
+type TextStream struct {
+	*eventStream
+	pending []GenerateTextResult
+	current GenerateTextResult
+
+	inputTokens     int
+	generatedTokens int
+}
 
-	if prompt == "" {
-		close(dataChan)
-		return dataChan, errors.New("prompt cannot be empty")
+// Next advances the stream to the next result; it returns false when the stream ends or fails
+func (s *TextStream) Next() bool {
+	for len(s.pending) == 0 {
+		data, ok := s.next()
+		if !ok {
+			if s.err == nil && (s.current.StopReason == "" || s.current.StopReason == NotFinished) {
+				s.err = io.ErrUnexpectedEOF
+			}
+			s.end()
+			return false
+		}
+
+		var generation generateTextResponse
+		if err := json.Unmarshal(data, &generation); err != nil {
+			s.err = err
+			s.end()
+			return false
+		}
+
+		s.pending = generation.Results
 	}
 
-	go func() {
-		defer close(dataChan)
+	s.current, s.pending = s.pending[0], s.pending[1:]
+	s.inputTokens = max(s.inputTokens, s.current.InputTokenCount)
+	s.generatedTokens = max(s.generatedTokens, s.current.GeneratedTokenCount)
+	return true
+}
 
-		m.CheckAndRefreshToken()
+// Current returns the result read by the last call to Next
+func (s *TextStream) Current() GenerateTextResult {
+	return s.current
+}
 
-		opts := &GenerateOptions{}
-		for _, opt := range options {
-			if opt != nil {
-				opt(opts)
-			}
-		}
+// OpenTextStream starts a streamed generation based on a given prompt and parameters.
+// The stream must be closed once done with.
+func (m *Client) OpenTextStream(model, prompt string, options ...GenerateOption) (*TextStream, error) {
+	return m.OpenTextStreamContext(context.Background(), model, prompt, options...)
+}
+
+// OpenTextStreamContext is like OpenTextStream but binds the stream to ctx.
+// Cancelling ctx aborts the request and ends the stream with the context's error.
+func (m *Client) OpenTextStreamContext(ctx context.Context, model, prompt string, options ...GenerateOption) (*TextStream, error) {
+	if prompt == "" {
+		return nil, errors.New("prompt cannot be empty")
+	}
 
-		payload := GenerateTextPayload{
-			ProjectID:  m.projectID,
-			Model:      model,
-			Prompt:     prompt,
-			Parameters: opts,
+	opts := &GenerateOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
 		}
+	}
 
-		responseChan, _ := m.generateTextStreamRequest(payload)
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return nil, err
+	}
 
-		for data := range responseChan {
-			for _, result := range data.Results {
-				dataChan <- result
-			}
-		}
-	}()
+	payload := GenerateTextPayload{
+		ProjectID:  projectID,
+		SpaceID:    spaceID,
+		Model:      model,
+		Prompt:     prompt,
+		Parameters: opts,
+	}
 
-	return dataChan, nil
+	return m.openTextStream(ctx, GenerateTextStreamEndpoint, payload, Usage{Operation: OperationTextCompletion, Model: model})
 }
 
-// generateTextStreamRequest sends the generate request and handles the response using the http package.
-// Closes the channel on non-200 response
-// If any error happens during the streaming, it will be logged and the channel will be closed
-func (m *Client) generateTextStreamRequest(payload GenerateTextPayload) (<-chan generateTextResponse, error) {
-	dataChan := make(chan generateTextResponse)
+// openTextStream sends a streamed generate request to endpoint, recording its usage once the stream ends
+func (m *Client) openTextStream(ctx context.Context, endpoint string, payload any, usage Usage) (*TextStream, error) {
+	usage.Stream = true
+	ctx, call := m.startCall(ctx, usage)
 
-	go func() {
-		defer close(dataChan)
+	req, err := m.newJSONRequest(ctx, http.MethodPost, endpoint, payload)
+	if err == nil {
+		req.Header.Set("Accept", "text/event-stream")
+	}
 
-		streamUrl := m.generateUrlFromEndpoint(GenerateTextStreamEndpoint)
+	var res *http.Response
+	if err == nil {
+		res, err = m.doRequest(req)
+	}
+	if err != nil {
+		usage.Err = err
+		call.end(usage)
+		return nil, err
+	}
 
-		payloadJSON, err := json.Marshal(payload)
-		if err != nil {
-			log.Println("error marshalling payload: ", err)
-			return
-		}
+	stream := &TextStream{eventStream: newEventStream(res.Body)}
+	stream.onFirstData = call.firstChunk
+	stream.onEnd = func() {
+		usage.InputTokens = stream.inputTokens
+		usage.GeneratedTokens = stream.generatedTokens
+		usage.StopReason = stream.current.StopReason
+		usage.Err = stream.err
+		call.end(usage)
+	}
 
-		req, err := http.NewRequest(http.MethodPost, streamUrl, bytes.NewBuffer(payloadJSON))
-		if err != nil {
-			log.Println("error creating request: ", err)
-			return
-		}
+	return stream, nil
+}
 
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+m.token.value)
-		req.Header.Set("Accept", "text/event-stream")
+// GenerateTextStream generates completion text channel (stream) based on a given prompt and parameters.
+// Errors opening the stream are returned; use OpenTextStream to also observe errors once streaming.
+func (m *Client) GenerateTextStream(model, prompt string, options ...GenerateOption) (<-chan GenerateTextResult, error) {
+	return m.GenerateTextStreamContext(context.Background(), model, prompt, options...)
+}
 
-		res, err := m.httpClient.DoWithRetry(req)
-		if err != nil {
-			log.Println("error making request: ", err)
-			return
-		}
+// GenerateTextStreamContext is like GenerateTextStream but binds the stream to ctx.
+// Cancelling ctx aborts the request and closes the channel.
+func (m *Client) GenerateTextStreamContext(ctx context.Context, model, prompt string, options ...GenerateOption) (<-chan GenerateTextResult, error) {
+	stream, err := m.OpenTextStreamContext(ctx, model, prompt, options...)
+	return textStreamChan(ctx, stream, err)
+}
 
-		defer res.Body.Close()
-		scanner := bufio.NewScanner(res.Body)
-		for scanner.Scan() {
-			line := scanner.Text()
+// textStreamChan pumps the results of stream into a channel until it ends or ctx is cancelled
+func textStreamChan(ctx context.Context, stream *TextStream, err error) (<-chan GenerateTextResult, error) {
+	dataChan := make(chan GenerateTextResult)
 
-			if !strings.HasPrefix(line, "data: ") {
-				continue
-			}
+	if err != nil {
+		close(dataChan)
+		return dataChan, err
+	}
 
-			data := line[6:]
-			var generation generateTextResponse
+	go func() {
+		defer close(dataChan)
+		defer stream.Close()
 
-			if err := json.Unmarshal([]byte(data), &generation); err != nil {
-				log.Println("error unmarshalling data: ", err)
+		for stream.Next() {
+			select {
+			case dataChan <- stream.Current():
+			case <-ctx.Done():
 				return
 			}
-			dataChan <- generation
 		}
 	}()
 
@@ -35,6 +35,11 @@ type GenerateOptions struct {
 	TimeLimit           *uint          `json:"time_limit,omitempty"`
 	TruncateInputTokens *uint          `json:"truncate_input_tokens,omitempty"`
 	ReturnOptions       *ReturnOptions `json:"return_options,omitempty"`
+
+	promptVariables  map[string]string          // Only used by deployments, see DeploymentParameters
+	batchConcurrency uint                       // Only used by GenerateTextBatch
+	batchProgress    func(completed, total int) // Only used by GenerateTextBatch
+	scope            callScope                  // Not used by deployments, which have their own
 }
 
 func WithDecodingMethod(decodingMethod string) GenerateOption {
@@ -115,6 +120,44 @@ func WithReturnOptions(inputText, generatedTokens, inputTokens, tokenLogProbs, t
 	}
 }
 
+// WithPromptVariables fills in the placeholders of a prompt template deployment; other calls ignore them
+func WithPromptVariables(promptVariables map[string]string) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.promptVariables = promptVariables
+	}
+}
+
+// WithBatchConcurrency limits how many prompts GenerateTextBatch generates at once
+func WithBatchConcurrency(concurrency uint) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.batchConcurrency = concurrency
+	}
+}
+
+// WithBatchProgress calls progress each time GenerateTextBatch finishes a prompt, successfully or not.
+// The calls are never concurrent.
+func WithBatchProgress(progress func(completed, total int)) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.batchProgress = progress
+	}
+}
+
+// WithProjectID runs the call in the given project instead of the client's project or space;
+// it cannot be combined with WithSpaceID
+func WithProjectID(projectID WatsonxProjectID) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.scope.projectID = projectID
+	}
+}
+
+// WithSpaceID runs the call in the given deployment space instead of the client's project or space;
+// it cannot be combined with WithProjectID
+func WithSpaceID(spaceID WatsonxSpaceID) GenerateOption {
+	return func(opts *GenerateOptions) {
+		opts.scope.spaceID = spaceID
+	}
+}
+
 func (gp *GenerateOptions) String() string {
 	return fmt.Sprintf(
 		"decodingMethod: %v\n"+
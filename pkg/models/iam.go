@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -24,6 +25,11 @@ type TokenResponse struct {
 }
 
 func GenerateToken(client Doer, watsonxApiKey WatsonxAPIKey, iamCloudHost string) (IAMToken, error) {
+	return GenerateTokenContext(context.Background(), client, watsonxApiKey, iamCloudHost)
+}
+
+// GenerateTokenContext is like GenerateToken but binds the IAM request to ctx
+func GenerateTokenContext(ctx context.Context, client Doer, watsonxApiKey WatsonxAPIKey, iamCloudHost string) (IAMToken, error) {
 	values := url.Values{
 		"grant_type": {"urn:ibm:params:oauth:grant-type:apikey"},
 		"apikey":     {watsonxApiKey},
@@ -31,12 +37,8 @@ func GenerateToken(client Doer, watsonxApiKey WatsonxAPIKey, iamCloudHost string
 
 	payload := strings.NewReader(values.Encode())
 
-	iamTokenEndpoint := url.URL{
-		Scheme:   "https",
-		Host:     iamCloudHost,
-		Path:     TokenPath,
-	}
-	req, err := http.NewRequest(http.MethodPost, iamTokenEndpoint.String(), payload)
+	iamTokenEndpoint := buildURL(iamCloudHost, TokenPath, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, iamTokenEndpoint, payload)
 	if err != nil {
 		return IAMToken{}, err
 	}
@@ -47,6 +49,10 @@ func GenerateToken(client Doer, watsonxApiKey WatsonxAPIKey, iamCloudHost string
 	if err != nil {
 		return IAMToken{}, err
 	}
+
+	if resp.StatusCode != http.StatusOK {
+		return IAMToken{}, newAPIError(resp)
+	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
@@ -67,6 +73,19 @@ func GenerateToken(client Doer, watsonxApiKey WatsonxAPIKey, iamCloudHost string
 
 }
 
+// NewIAMToken creates a token for custom Authenticators; a zero expiration means the token does not expire
+func NewIAMToken(value string, expiration time.Time) IAMToken {
+	return IAMToken{value, expiration}
+}
+
 func (t *IAMToken) Expired() bool {
-	return t.expiration.Before(time.Now())
+	return t.expiresWithin(0)
+}
+
+// expiresWithin reports whether the token expires in less than d
+func (t *IAMToken) expiresWithin(d time.Duration) bool {
+	if t.expiration.IsZero() {
+		return false
+	}
+	return t.expiration.Before(time.Now().Add(d))
 }
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// redacted replaces the secrets, and optionally the prompts, in the logs
+const redacted = "[REDACTED]"
+
+// promptFields are the fields of watsonx payloads carrying prompt text
+var promptFields = []string{"input", "inputs", "query", "messages", "prompt_variables"}
+
+var bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`)
+
+// discardHandler drops every record; it is the handler of clients without WithLogger
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// redactingHandler scrubs the API key and bearer tokens of the client from the records it passes on,
+// wherever they appear, e.g. in an error message echoing a request
+type redactingHandler struct {
+	slog.Handler
+	secrets func() []string
+}
+
+func newRedactingLogger(logger *slog.Logger, secrets func() []string) *slog.Logger {
+	if logger == nil {
+		return slog.New(discardHandler{})
+	}
+	return slog.New(&redactingHandler{Handler: logger.Handler(), secrets: secrets})
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	secrets := h.secrets()
+
+	scrubbed := slog.NewRecord(r.Time, r.Level, redact(r.Message, secrets), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		scrubbed.AddAttrs(redactAttr(a, secrets))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, scrubbed)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	secrets := h.secrets()
+	scrubbed := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		scrubbed[i] = redactAttr(a, secrets)
+	}
+	return &redactingHandler{Handler: h.Handler.WithAttrs(scrubbed), secrets: h.secrets}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{Handler: h.Handler.WithGroup(name), secrets: h.secrets}
+}
+
+func redactAttr(a slog.Attr, secrets []string) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redact(a.Value.String(), secrets))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		scrubbed := make([]any, len(group))
+		for i, member := range group {
+			scrubbed[i] = redactAttr(member, secrets)
+		}
+		return slog.Group(a.Key, scrubbed...)
+	case slog.KindAny:
+		text := fmt.Sprint(a.Value.Any())
+		if scrubbed := redact(text, secrets); scrubbed != text {
+			return slog.String(a.Key, scrubbed)
+		}
+	}
+
+	return a
+}
+
+func redact(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, redacted)
+		}
+	}
+	return bearerPattern.ReplaceAllString(text, "Bearer "+redacted)
+}
+
+// redactPrompts replaces the prompt fields of a JSON payload
+func redactPrompts(payloadJSON []byte) string {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(payloadJSON, &payload); err != nil {
+		return redacted
+	}
+
+	quoted, _ := json.Marshal(redacted)
+	for _, field := range promptFields {
+		if _, ok := payload[field]; ok {
+			payload[field] = quoted
+		}
+	}
+
+	if parameters, ok := payload["parameters"]; ok {
+		payload["parameters"] = json.RawMessage(redactPrompts(parameters))
+	}
+
+	scrubbed, _ := json.Marshal(payload)
+	return string(scrubbed)
+}
+
+// logRequest logs the request about to be sent, with its payload
+func (m *Client) logRequest(ctx context.Context, method, endpoint string, payloadJSON []byte) {
+	if !m.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	body := string(payloadJSON)
+	if m.redactPrompts {
+		body = redactPrompts(payloadJSON)
+	}
+
+	m.logger.LogAttrs(ctx, slog.LevelDebug, "watsonx request",
+		slog.String("method", method),
+		slog.String("endpoint", endpoint),
+		slog.String("body", body),
+	)
+}
+
+// logRetries returns a retry option logging each retry of the request to rawURL,
+// after calling any OnRetryFunc of the policy
+func (m *Client) logRetries(ctx context.Context, method, rawURL string, policy []RetryOption) RetryOption {
+	endpoint := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		endpoint = u.Path
+	}
+
+	return chainOnRetry(policy, func(attempt uint, err error) {
+		m.logger.LogAttrs(ctx, slog.LevelDebug, "watsonx retry",
+			slog.String("method", method),
+			slog.String("endpoint", endpoint),
+			slog.Uint64("attempt", uint64(attempt)),
+			slog.Any("error", err),
+		)
+	})
+}
+
+// loggingDoer logs the outcome of every request sent through it, IAM requests included.
+// Neither headers nor bodies are logged.
+type loggingDoer struct {
+	Doer
+	logger *slog.Logger
+}
+
+func (d *loggingDoer) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := d.Doer.Do(req)
+	d.logResponse(req, res, err, start)
+	return res, err
+}
+
+func (d *loggingDoer) DoWithRetry(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := d.Doer.DoWithRetry(req)
+	d.logResponse(req, res, err, start)
+	return res, err
+}
+
+func (d *loggingDoer) logResponse(req *http.Request, res *http.Response, err error, start time.Time) {
+	ctx := req.Context()
+	if !d.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.URL.Path),
+		slog.Duration("duration", time.Since(start)),
+	}
+
+	status := 0
+	if res != nil {
+		status = res.StatusCode
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	if status != 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	d.logger.LogAttrs(ctx, slog.LevelDebug, "watsonx response", attrs...)
+}
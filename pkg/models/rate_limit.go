@@ -0,0 +1,188 @@
+package models
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type RateLimitOption func(*rateLimitConfig)
+
+type rateLimitConfig struct {
+	requestsPerSecond float64
+	burst             int
+	maxConcurrent     int
+	tokensPerMinute   int
+}
+
+// WithRequestsPerSecond sends at most rps requests per second on average, with bursts of up to burst requests.
+// Retries count as requests of their own.
+func WithRequestsPerSecond(rps float64, burst int) RateLimitOption {
+	return func(c *rateLimitConfig) {
+		c.requestsPerSecond = rps
+		c.burst = burst
+	}
+}
+
+// WithMaxConcurrentRequests caps the calls in flight, streams counting until they are closed
+func WithMaxConcurrentRequests(maxConcurrent int) RateLimitOption {
+	return func(c *rateLimitConfig) {
+		c.maxConcurrent = maxConcurrent
+	}
+}
+
+// WithTokensPerMinute holds calls back once the input and generated tokens of the past calls exceed
+// the budget, which is replenished continuously at tokensPerMinute
+func WithTokensPerMinute(tokensPerMinute int) RateLimitOption {
+	return func(c *rateLimitConfig) {
+		c.tokensPerMinute = tokensPerMinute
+	}
+}
+
+// rateLimiter shapes the calls of a client; its zero limits are disabled
+type rateLimiter struct {
+	requests *tokenBucket
+	tokens   *tokenBucket
+	slots    chan struct{}
+}
+
+func newRateLimiter(options ...RateLimitOption) *rateLimiter {
+	config := &rateLimitConfig{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(config)
+		}
+	}
+
+	l := &rateLimiter{}
+
+	if config.requestsPerSecond > 0 {
+		l.requests = newTokenBucket(config.requestsPerSecond, float64(max(config.burst, 1)))
+	}
+	if config.tokensPerMinute > 0 {
+		l.tokens = newTokenBucket(float64(config.tokensPerMinute)/60, float64(config.tokensPerMinute))
+	}
+	if config.maxConcurrent > 0 {
+		l.slots = make(chan struct{}, config.maxConcurrent)
+	}
+
+	return l
+}
+
+// acquire waits until a call may be sent; release must be called once it is done
+func (l *rateLimiter) acquire(ctx context.Context) (release func(), err error) {
+	release = func() {}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-l.slots })
+		}
+	}
+
+	if err := l.waitRequest(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	if l.tokens != nil {
+		// the tokens of the call are only known once done, see consume
+		if err := l.tokens.wait(ctx, 0); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// waitRequest waits until a request may be sent. Every request counts against the requests per second,
+// retries and replays included, while the concurrency slot and the token budget are held once per call.
+func (l *rateLimiter) waitRequest(ctx context.Context) error {
+	if l.requests == nil {
+		return nil
+	}
+	return l.requests.wait(ctx, 1)
+}
+
+// consume charges the tokens of a call to the budget
+func (l *rateLimiter) consume(tokens int) {
+	if l.tokens != nil && tokens > 0 {
+		l.tokens.take(float64(tokens))
+	}
+}
+
+// tokenBucket holds up to capacity tokens, refilled at rate tokens per second
+type tokenBucket struct {
+	mu        sync.Mutex
+	rate      float64
+	capacity  float64
+	available float64
+	last      time.Time
+}
+
+func newTokenBucket(rate, capacity float64) *tokenBucket {
+	return &tokenBucket{
+		rate:      rate,
+		capacity:  capacity,
+		available: capacity,
+		last:      time.Now(),
+	}
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	b.available = min(b.capacity, b.available+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait waits until a token is available, then takes n tokens
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	for {
+		b.mu.Lock()
+		b.refill()
+		if b.available >= 1 {
+			b.available -= n
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.available) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// take removes n tokens, possibly going into debt
+func (b *tokenBucket) take(n float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.available -= n
+}
+
+// releaseOnClose releases the rate limiter once the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	RerankEndpoint string = GenerationEndpoint + "/rerank"
+)
+
+type RerankPayload struct {
+	ProjectID  string         `json:"project_id,omitempty"`
+	SpaceID    string         `json:"space_id,omitempty"`
+	Model      string         `json:"model_id"`
+	Query      string         `json:"query"`
+	Inputs     []RerankInput  `json:"inputs"`
+	Parameters *RerankOptions `json:"parameters,omitempty"`
+}
+
+type RerankInput struct {
+	Text string `json:"text"`
+}
+
+type RerankResponse struct {
+	Model           string         `json:"model_id"`
+	Results         []RerankResult `json:"results"`
+	Query           string         `json:"query,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	InputTokenCount int            `json:"input_token_count"`
+}
+
+// RerankResult is the score of a document, with Index its position in the given documents.
+// Results are ordered from the most to the least relevant.
+type RerankResult struct {
+	Index int          `json:"index"`
+	Score float64      `json:"score"`
+	Input *RerankInput `json:"input,omitempty"`
+}
+
+// Rerank scores the relevance of the documents to the query using the specified model.
+func (m *Client) Rerank(model, query string, documents []string, options ...RerankOption) (RerankResponse, error) {
+	return m.RerankContext(context.Background(), model, query, documents, options...)
+}
+
+// RerankContext is like Rerank but binds the request to ctx.
+func (m *Client) RerankContext(ctx context.Context, model, query string, documents []string, options ...RerankOption) (RerankResponse, error) {
+	if query == "" {
+		return RerankResponse{}, errors.New("query cannot be empty")
+	}
+	if len(documents) == 0 {
+		return RerankResponse{}, errors.New("documents cannot be empty")
+	}
+
+	opts := &RerankOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return RerankResponse{}, err
+	}
+
+	inputs := make([]RerankInput, len(documents))
+	for i, document := range documents {
+		inputs[i] = RerankInput{Text: document}
+	}
+
+	payload := RerankPayload{
+		ProjectID:  projectID,
+		SpaceID:    spaceID,
+		Model:      model,
+		Query:      query,
+		Inputs:     inputs,
+		Parameters: opts,
+	}
+
+	usage := Usage{Operation: OperationRerank, Model: model}
+	ctx, call := m.startCall(ctx, usage)
+	response, err := m.rerankRequest(ctx, payload)
+
+	usage.InputTokens = response.InputTokenCount
+	usage.Err = err
+	call.end(usage)
+
+	return response, err
+}
+
+func (m *Client) rerankRequest(ctx context.Context, payload RerankPayload) (RerankResponse, error) {
+	req, err := m.newJSONRequest(ctx, http.MethodPost, RerankEndpoint, payload)
+	if err != nil {
+		return RerankResponse{}, err
+	}
+
+	res, err := m.doRequest(req)
+	if err != nil {
+		return RerankResponse{}, err
+	}
+	defer res.Body.Close()
+
+	var rerankRes RerankResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&rerankRes); err != nil {
+		return RerankResponse{}, err
+	}
+
+	return rerankRes, nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import "fmt"
+
+type RerankOption func(*RerankOptions)
+
+type RerankOptions struct {
+	TruncateInputTokens *uint                `json:"truncate_input_tokens,omitempty"`
+	ReturnOptions       *RerankReturnOptions `json:"return_options,omitempty"`
+
+	scope callScope
+}
+
+type RerankReturnOptions struct {
+	TopN   *uint `json:"top_n,omitempty"`
+	Inputs bool  `json:"inputs"`
+	Query  bool  `json:"query"`
+}
+
+func WithRerankTruncateInputTokens(truncateInputTokens uint) RerankOption {
+	return func(opts *RerankOptions) {
+		opts.TruncateInputTokens = &truncateInputTokens
+	}
+}
+
+// WithRerankTopN returns only the n highest ranked documents
+func WithRerankTopN(topN uint) RerankOption {
+	return func(opts *RerankOptions) {
+		if opts.ReturnOptions == nil {
+			opts.ReturnOptions = &RerankReturnOptions{}
+		}
+		opts.ReturnOptions.TopN = &topN
+	}
+}
+
+// WithRerankReturnOptions returns the documents and the query along with the scores
+func WithRerankReturnOptions(inputs, query bool) RerankOption {
+	return func(opts *RerankOptions) {
+		if opts.ReturnOptions == nil {
+			opts.ReturnOptions = &RerankReturnOptions{}
+		}
+		opts.ReturnOptions.Inputs = inputs
+		opts.ReturnOptions.Query = query
+	}
+}
+
+// WithRerankProjectID runs the call in the given project instead of the client's project or space;
+// it cannot be combined with WithRerankSpaceID
+func WithRerankProjectID(projectID WatsonxProjectID) RerankOption {
+	return func(opts *RerankOptions) {
+		opts.scope.projectID = projectID
+	}
+}
+
+// WithRerankSpaceID runs the call in the given deployment space instead of the client's project or space;
+// it cannot be combined with WithRerankProjectID
+func WithRerankSpaceID(spaceID WatsonxSpaceID) RerankOption {
+	return func(opts *RerankOptions) {
+		opts.scope.spaceID = spaceID
+	}
+}
+
+func (rp *RerankOptions) String() string {
+	return fmt.Sprintf(
+		"truncateInputTokens: %v\n"+
+			"returnOptions: %v\n",
+		rp.TruncateInputTokens,
+		rp.ReturnOptions,
+	)
+}
@@ -1,10 +1,14 @@
 package models
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"math"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -19,15 +23,24 @@ type Timer interface {
 // RetryIfFunc determines whether a retry should be attempted based on the error.
 type RetryIfFunc func(error) bool
 
+// BackoffStrategy computes the delay before the given retry attempt (starting at 1),
+// from the base backoff and the previous delay.
+type BackoffStrategy func(attempt uint, base, previous time.Duration) time.Duration
+
 // RetryConfig contains configuration options for the retry mechanism.
 type RetryConfig struct {
-	retries   uint
-	backoff   time.Duration
-	maxJitter time.Duration
-	onRetry   OnRetryFunc
-	retryIf   RetryIfFunc
-	timer     Timer
-	context   context.Context
+	retries    uint
+	backoff    time.Duration
+	maxBackoff time.Duration
+	maxJitter  time.Duration
+	strategy   BackoffStrategy
+	retryAfter bool
+	onRetry    OnRetryFunc
+	retryIf    RetryIfFunc
+	timer      Timer
+	context    context.Context
+
+	beforeRetry func(ctx context.Context) error // e.g. waits for the rate limiter of the client
 }
 
 // RetryOption is a function type for modifying RetryConfig options.
@@ -43,14 +56,104 @@ func (t timerImpl) After(d time.Duration) <-chan time.Time {
 // newDefaultRetryConfig creates a default RetryConfig with sensible defaults.
 func newDefaultRetryConfig() *RetryConfig {
 	return &RetryConfig{
-		retries:   3,
-		backoff:   1 * time.Second,
-		maxJitter: 1 * time.Second,
-		onRetry:   func(n uint, err error) {},                 // no-op onRetry by default
-		retryIf:   func(err error) bool { return err != nil }, // retry on any error by default
-		timer:     &timerImpl{},
-		context:   context.Background(),
+		retries:    3,
+		backoff:    1 * time.Second,
+		maxJitter:  1 * time.Second,
+		strategy:   ConstantBackoff,
+		retryAfter: true,
+		onRetry:    func(n uint, err error) {}, // no-op onRetry by default
+		retryIf:    DefaultRetryIf,
+		timer:      &timerImpl{},
+		context:    context.Background(),
+	}
+}
+
+// ConstantBackoff waits the base backoff before every retry.
+func ConstantBackoff(attempt uint, base, previous time.Duration) time.Duration {
+	return base
+}
+
+// ExponentialBackoff multiplies the base backoff by multiplier after every retry: base, base*multiplier, ...
+func ExponentialBackoff(multiplier float64) BackoffStrategy {
+	return func(attempt uint, base, previous time.Duration) time.Duration {
+		delay := float64(base) * math.Pow(multiplier, float64(attempt-1))
+		if delay >= math.MaxInt64 {
+			// the conversion would overflow without WithMaxBackoff
+			return math.MaxInt64
+		}
+		return time.Duration(delay)
+	}
+}
+
+// DecorrelatedJitterBackoff waits a random duration between the base backoff and three times the previous delay.
+func DecorrelatedJitterBackoff(attempt uint, base, previous time.Duration) time.Duration {
+	upper := 3 * previous
+	if upper <= base {
+		return base
+	}
+	return base + time.Duration(rand.Int63n(int64(upper-base)))
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a 429 or 503 response.
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Header == nil {
+		return 0, false
+	}
+
+	if apiErr.StatusCode != http.StatusTooManyRequests && apiErr.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	value := apiErr.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// DefaultRetryIf retries network errors, rate limiting (429) and server errors (5xx).
+// Client errors such as 400 or 401 and cancelled contexts are not retried.
+func DefaultRetryIf(err error) bool {
+	if err == nil {
+		return false
 	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return true
+}
+
+// retryPolicyKey is the context key of the retry options of a request
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy returns a copy of ctx carrying retry options for the calls made with it.
+// They are applied after the client's retry policy, so they override it.
+func ContextWithRetryPolicy(ctx context.Context, options ...RetryOption) context.Context {
+	policy := append(RetryPolicyFromContext(ctx), options...)
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// RetryPolicyFromContext returns the retry options carried by ctx; custom Doers may use it to honor them
+func RetryPolicyFromContext(ctx context.Context) []RetryOption {
+	policy, _ := ctx.Value(retryPolicyKey{}).([]RetryOption)
+	return append([]RetryOption(nil), policy...)
 }
 
 // RetryableFuncWithResponse represents a function that returns an HTTP response or an error.
@@ -66,7 +169,11 @@ func Retry(retryableFunc RetryableFuncWithResponse, options ...RetryOption) (*ht
 		}
 	}
 
+	// the first attempt is always made
+	opts.retries = max(opts.retries, 1)
+
 	var lastErr error
+	var backoffDuration time.Duration
 	for n := uint(0); n < opts.retries; n++ {
 		if err := opts.context.Err(); err != nil {
 			return nil, err
@@ -78,7 +185,7 @@ func Retry(retryableFunc RetryableFuncWithResponse, options ...RetryOption) (*ht
 		}
 
 		if err == nil && resp != nil {
-			err = errors.New(resp.Status)
+			err = newAPIError(resp)
 		}
 
 		if !opts.retryIf(err) {
@@ -86,12 +193,24 @@ func Retry(retryableFunc RetryableFuncWithResponse, options ...RetryOption) (*ht
 		}
 
 		lastErr = err
+		if n+1 == opts.retries {
+			// no retry left, so neither notify nor wait
+			break
+		}
 		opts.onRetry(n+1, err)
 
-		backoffDuration := opts.backoff
+		backoffDuration = opts.strategy(n+1, opts.backoff, max(backoffDuration, opts.backoff))
 		if opts.maxJitter > 0 {
 			jitter := time.Duration(rand.Int63n(int64(opts.maxJitter)))
-			backoffDuration += jitter
+			backoffDuration = min(backoffDuration, math.MaxInt64-jitter) + jitter
+		}
+
+		if delay, ok := retryAfter(err); ok && opts.retryAfter {
+			backoffDuration = delay
+		}
+
+		if opts.maxBackoff > 0 && backoffDuration > opts.maxBackoff {
+			backoffDuration = opts.maxBackoff
 		}
 
 		select {
@@ -99,12 +218,19 @@ func Retry(retryableFunc RetryableFuncWithResponse, options ...RetryOption) (*ht
 		case <-opts.context.Done():
 			return nil, opts.context.Err()
 		}
+
+		if opts.beforeRetry != nil {
+			if err := opts.beforeRetry(opts.context); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return nil, lastErr
 }
 
-// WithRetries sets the number of retries for the retry configuration.
+// WithRetries sets the number of attempts for the retry configuration, the first one included.
+// 0 is the same as 1, a single attempt without retries.
 func WithRetries(retries uint) RetryOption {
 	return func(cfg *RetryConfig) {
 		cfg.retries = retries
@@ -118,6 +244,48 @@ func WithBackoff(backoff time.Duration) RetryOption {
 	}
 }
 
+// WithMaxBackoff caps the delay between retries, including delays requested through Retry-After.
+func WithMaxBackoff(maxBackoff time.Duration) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.maxBackoff = maxBackoff
+	}
+}
+
+// WithBackoffStrategy sets how the delay grows between retries.
+func WithBackoffStrategy(strategy BackoffStrategy) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.strategy = strategy
+	}
+}
+
+// WithExponentialBackoff multiplies the backoff by multiplier after every retry.
+func WithExponentialBackoff(multiplier float64) RetryOption {
+	return WithBackoffStrategy(ExponentialBackoff(multiplier))
+}
+
+// WithDecorrelatedJitter randomizes the backoff between the base backoff and three times the previous delay.
+// The strategy is random by itself, so it disables the additional jitter.
+func WithDecorrelatedJitter() RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.strategy = DecorrelatedJitterBackoff
+		cfg.maxJitter = 0
+	}
+}
+
+// WithRetryAfter sets whether the Retry-After header of 429 and 503 responses overrides the backoff; it does by default.
+func WithRetryAfter(honor bool) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.retryAfter = honor
+	}
+}
+
+// WithTimer sets the timer used to wait between retries.
+func WithTimer(timer Timer) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.timer = timer
+	}
+}
+
 // WithMaxJitter sets the maximum jitter duration to add to the backoff.
 func WithMaxJitter(maxJitter time.Duration) RetryOption {
 	return func(cfg *RetryConfig) {
@@ -132,6 +300,32 @@ func WithOnRetry(onRetry OnRetryFunc) RetryOption {
 	}
 }
 
+// chainOnRetry returns an option calling onRetry after the OnRetryFunc set by the options, if any
+func chainOnRetry(options []RetryOption, onRetry OnRetryFunc) RetryOption {
+	cfg := newDefaultRetryConfig()
+	for _, opt := range options {
+		if opt != nil {
+			opt(cfg)
+		}
+	}
+	previous := cfg.onRetry
+
+	return WithOnRetry(func(n uint, err error) {
+		if previous != nil {
+			previous(n, err)
+		}
+		onRetry(n, err)
+	})
+}
+
+// withBeforeRetry sets a function called after the backoff, right before each retry is sent;
+// its error ends the retries
+func withBeforeRetry(beforeRetry func(ctx context.Context) error) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.beforeRetry = beforeRetry
+	}
+}
+
 // WithRetryIf sets the condition to determine whether to retry based on the error.
 func WithRetryIf(retryIf RetryIfFunc) RetryOption {
 	return func(cfg *RetryConfig) {
@@ -139,6 +333,13 @@ func WithRetryIf(retryIf RetryIfFunc) RetryOption {
 	}
 }
 
+// WithContext sets the context that bounds the retries; once it is done no further attempts are made.
+func WithContext(ctx context.Context) RetryOption {
+	return func(cfg *RetryConfig) {
+		cfg.context = ctx
+	}
+}
+
 // Custom wrapper for http.Client that implements the Doer interface.
 // - Do
 // - DoWithRetry
@@ -147,8 +348,16 @@ type HttpClient struct {
 }
 
 func NewHttpClient() *HttpClient {
+	return NewHttpClientWith(nil)
+}
+
+// NewHttpClientWith wraps the given http.Client; a nil client is replaced with a zero http.Client
+func NewHttpClientWith(httpClient *http.Client) *HttpClient {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
 	return &HttpClient{
-		httpClient: &http.Client{},
+		httpClient: httpClient,
 	}
 }
 
@@ -156,10 +365,65 @@ func (c *HttpClient) Do(req *http.Request) (*http.Response, error) {
 	return c.httpClient.Do(req)
 }
 
+// DoWithRetry sends the request, retrying on failure until the request's context is done.
+// The retry policy carried by the request's context applies, see ContextWithRetryPolicy.
+// The body is rebuilt before every retry so that each attempt sends the same payload.
 func (c *HttpClient) DoWithRetry(req *http.Request) (*http.Response, error) {
+	if err := makeReplayable(req); err != nil {
+		return nil, err
+	}
+
+	options := append([]RetryOption{WithContext(req.Context())}, RetryPolicyFromContext(req.Context())...)
+
+	attempt := 0
 	return Retry(
 		func() (*http.Response, error) {
-			return c.httpClient.Do(req)
+			attemptReq := req
+			if attempt > 0 {
+				var err error
+				if attemptReq, err = rewindRequest(req); err != nil {
+					return nil, err
+				}
+			}
+			attempt++
+
+			return c.httpClient.Do(attemptReq)
 		},
+		options...,
 	)
 }
+
+// makeReplayable buffers the body of requests that cannot rebuild it on their own
+func makeReplayable(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return nil
+}
+
+// rewindRequest returns a copy of the request with a fresh body
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+
+	return clone, nil
+}
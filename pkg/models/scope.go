@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+)
+
+// callScope is the project or space a single call runs in, instead of the client's
+type callScope struct {
+	projectID WatsonxProjectID
+	spaceID   WatsonxSpaceID
+}
+
+// scope returns the project or space of a call: the one set by its options, if any, or the client's
+func (m *Client) scope(s callScope) (WatsonxProjectID, WatsonxSpaceID, error) {
+	if s.projectID == "" && s.spaceID == "" {
+		return m.projectID, m.spaceID, nil
+	}
+
+	if err := validateScope(s.projectID, s.spaceID); err != nil {
+		return "", "", err
+	}
+	return s.projectID, s.spaceID, nil
+}
+
+// resolveScope settles the project and space of the client: an explicit option overrides
+// the other ID read from the environment, but setting both explicitly is an error
+func resolveScope(opts *ClientOptions) error {
+	switch {
+	case opts.projectIDSet && opts.spaceIDSet:
+		return errors.New("both WithWatsonxProjectID and WithWatsonxSpaceID provided; set only one")
+	case opts.projectIDSet:
+		opts.spaceID = ""
+	case opts.spaceIDSet:
+		opts.projectID = ""
+	}
+
+	return validateScope(opts.projectID, opts.spaceID)
+}
+
+// validateScope checks that exactly one of project ID and space ID is set
+func validateScope(projectID WatsonxProjectID, spaceID WatsonxSpaceID) error {
+	if projectID == "" && spaceID == "" {
+		return errors.New("no watsonx project ID or space ID provided")
+	}
+	if projectID != "" && spaceID != "" {
+		return errors.New("both a watsonx project ID and a space ID provided; set only one")
+	}
+	return nil
+}
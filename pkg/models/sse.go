@@ -0,0 +1,156 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// sseDone is the data of the event some servers send to mark the end of the stream
+const sseDone = "[DONE]"
+
+// sseErrorEvent is the type of the event watsonx sends when a stream fails
+const sseErrorEvent = "error"
+
+// maxSSEEventSize bounds the size of a single server-sent event line
+const maxSSEEventSize = 1024 * 1024
+
+// sseEvent is a single server-sent event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  []byte
+}
+
+// sseReader reads server-sent events from a text/event-stream body
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSEEventSize)
+	return &sseReader{scanner}
+}
+
+// Next returns the next event that carries data.
+// Returns io.EOF once the stream is exhausted
+func (r *sseReader) Next() (sseEvent, error) {
+	var event sseEvent
+	var data [][]byte
+
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+
+		if len(line) == 0 {
+			// a blank line dispatches the event, if there is one
+			if len(data) > 0 {
+				event.Data = bytes.Join(data, []byte("\n"))
+				return event, nil
+			}
+			event = sseEvent{}
+			continue
+		}
+
+		if line[0] == ':' {
+			continue // comment
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+
+		switch string(field) {
+		case "id":
+			event.ID = string(value)
+		case "event":
+			event.Event = string(value)
+		case "data":
+			data = append(data, append([]byte(nil), value...))
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return sseEvent{}, err
+	}
+
+	// the stream may end without a trailing blank line
+	if len(data) > 0 {
+		event.Data = bytes.Join(data, []byte("\n"))
+		return event, nil
+	}
+
+	return sseEvent{}, io.EOF
+}
+
+// eventStream reads the data of consecutive events of a watsonx stream, turning error events into errors
+type eventStream struct {
+	body   io.ReadCloser
+	events *sseReader
+	err    error
+
+	onFirstData func() // Called once the first event data is read
+	onEnd       func() // Called once the stream ends or is closed
+	endOnce     sync.Once
+	started     bool
+}
+
+func newEventStream(body io.ReadCloser) *eventStream {
+	return &eventStream{
+		body:   body,
+		events: newSSEReader(body),
+	}
+}
+
+// next returns the data of the next event; false means the stream ended, see Err
+func (s *eventStream) next() ([]byte, bool) {
+	if s.err != nil {
+		return nil, false
+	}
+
+	event, err := s.events.Next()
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		return nil, false
+	}
+
+	if string(event.Data) == sseDone {
+		return nil, false
+	}
+
+	if event.Event == sseErrorEvent {
+		s.err = newStreamAPIError(event.Data)
+		return nil, false
+	}
+
+	if !s.started {
+		s.started = true
+		if s.onFirstData != nil {
+			s.onFirstData()
+		}
+	}
+
+	return event.Data, true
+}
+
+// end runs onEnd once; the typed streams call it when they stop, with their final error set
+func (s *eventStream) end() {
+	s.endOnce.Do(func() {
+		if s.onEnd != nil {
+			s.onEnd()
+		}
+	})
+}
+
+// Err returns the error that stopped the stream, if any
+func (s *eventStream) Err() error {
+	return s.err
+}
+
+// Close releases the underlying connection
+func (s *eventStream) Close() error {
+	s.end()
+	return s.body.Close()
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	// InstrumentationName names the tracer and meter of the client
+	InstrumentationName = "github.com/IBM/watsonx-go/pkg/models"
+
+	genAISystem = "ibm.watsonx.ai"
+)
+
+// Attribute keys of the OpenTelemetry gen_ai semantic conventions, and of watsonx
+const (
+	attrGenAISystem        = attribute.Key("gen_ai.system")
+	attrGenAIOperation     = attribute.Key("gen_ai.operation.name")
+	attrGenAIRequestModel  = attribute.Key("gen_ai.request.model")
+	attrGenAIInputTokens   = attribute.Key("gen_ai.usage.input_tokens")
+	attrGenAIOutputTokens  = attribute.Key("gen_ai.usage.output_tokens")
+	attrGenAIFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+	attrGenAITokenType     = attribute.Key("gen_ai.token.type")
+	attrServerAddress      = attribute.Key("server.address")
+	attrErrorType          = attribute.Key("error.type")
+	attrRetryAttempt       = attribute.Key("retry.attempt")
+	attrWatsonxStream      = attribute.Key("watsonx.stream")
+	attrWatsonxDeployment  = attribute.Key("watsonx.deployment_id")
+	attrWatsonxAuth        = attribute.Key("watsonx.authenticator")
+)
+
+// telemetry holds the OpenTelemetry instruments of a client; they are no-ops unless providers are set
+type telemetry struct {
+	tracer           trace.Tracer
+	duration         metric.Float64Histogram
+	timeToFirstChunk metric.Float64Histogram
+	tokenUsage       metric.Int64Histogram
+}
+
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) *telemetry {
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	meter := meterProvider.Meter(InstrumentationName)
+	noopMeter := metricnoop.NewMeterProvider().Meter(InstrumentationName)
+
+	// buckets advised by the gen_ai semantic conventions
+	durationBuckets := metric.WithExplicitBucketBoundaries(0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92)
+	tokenBuckets := metric.WithExplicitBucketBoundaries(1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864)
+
+	t := &telemetry{tracer: tracerProvider.Tracer(InstrumentationName)}
+	var err error
+
+	t.duration, err = meter.Float64Histogram("gen_ai.client.operation.duration",
+		metric.WithDescription("Duration of watsonx requests"), metric.WithUnit("s"), durationBuckets)
+	if err != nil {
+		t.duration, _ = noopMeter.Float64Histogram("")
+	}
+
+	t.timeToFirstChunk, err = meter.Float64Histogram("gen_ai.client.operation.time_to_first_chunk",
+		metric.WithDescription("Time until the first chunk of a watsonx stream"), metric.WithUnit("s"), durationBuckets)
+	if err != nil {
+		t.timeToFirstChunk, _ = noopMeter.Float64Histogram("")
+	}
+
+	t.tokenUsage, err = meter.Int64Histogram("gen_ai.client.token.usage",
+		metric.WithDescription("Input and output tokens of watsonx requests"), metric.WithUnit("{token}"), tokenBuckets)
+	if err != nil {
+		t.tokenUsage, _ = noopMeter.Int64Histogram("")
+	}
+
+	return t
+}
+
+// startSpan starts the span of a request, named after its operation and model, if any
+func (m *Client) startSpan(ctx context.Context, usage Usage) (context.Context, trace.Span) {
+	name := usage.Operation
+	if usage.Model != "" {
+		name += " " + usage.Model
+	} else if usage.DeploymentID != "" {
+		name += " " + usage.DeploymentID
+	}
+
+	return m.telemetry.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(m.usageAttributes(usage)...),
+	)
+}
+
+// endSpan records the outcome of a request on its span and in the metrics
+func (m *Client) endSpan(ctx context.Context, span trace.Span, usage Usage) {
+	attributes := m.usageAttributes(usage)
+	if usage.Err != nil {
+		attributes = append(attributes, attrErrorType.String(errorType(usage.Err)))
+	}
+
+	m.telemetry.duration.Record(ctx, usage.Latency.Seconds(), metric.WithAttributes(attributes...))
+	if usage.InputTokens > 0 {
+		m.telemetry.tokenUsage.Record(ctx, int64(usage.InputTokens), metric.WithAttributes(append(attributes, attrGenAITokenType.String("input"))...))
+	}
+	if usage.GeneratedTokens > 0 {
+		m.telemetry.tokenUsage.Record(ctx, int64(usage.GeneratedTokens), metric.WithAttributes(append(attributes, attrGenAITokenType.String("output"))...))
+	}
+
+	if !span.IsRecording() {
+		span.End()
+		return
+	}
+
+	span.SetAttributes(
+		attrGenAIInputTokens.Int(usage.InputTokens),
+		attrGenAIOutputTokens.Int(usage.GeneratedTokens),
+	)
+	if usage.StopReason != "" {
+		span.SetAttributes(attrGenAIFinishReasons.StringSlice([]string{usage.StopReason}))
+	}
+	if usage.Err != nil {
+		span.SetAttributes(attrErrorType.String(errorType(usage.Err)))
+		span.RecordError(usage.Err)
+		span.SetStatus(codes.Error, usage.Err.Error())
+	}
+
+	span.End()
+}
+
+// recordFirstChunk records the time to the first chunk of a stream
+func (m *Client) recordFirstChunk(ctx context.Context, span trace.Span, usage Usage, elapsed time.Duration) {
+	m.telemetry.timeToFirstChunk.Record(ctx, elapsed.Seconds(), metric.WithAttributes(m.usageAttributes(usage)...))
+	span.AddEvent("first_chunk")
+}
+
+func (m *Client) usageAttributes(usage Usage) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		attrGenAISystem.String(genAISystem),
+		attrGenAIOperation.String(usage.Operation),
+		attrServerAddress.String(m.url),
+	}
+	if usage.Model != "" {
+		attributes = append(attributes, attrGenAIRequestModel.String(usage.Model))
+	}
+	if usage.DeploymentID != "" {
+		attributes = append(attributes, attrWatsonxDeployment.String(usage.DeploymentID))
+	}
+	if usage.Stream {
+		attributes = append(attributes, attrWatsonxStream.Bool(true))
+	}
+	return attributes
+}
+
+// retrySpanEvents returns a retry option adding an event to the span of ctx on each retry,
+// after calling any OnRetryFunc of the policy
+func retrySpanEvents(ctx context.Context, policy []RetryOption) RetryOption {
+	span := trace.SpanFromContext(ctx)
+
+	return chainOnRetry(policy, func(attempt uint, err error) {
+		span.AddEvent("retry", trace.WithAttributes(
+			attrRetryAttempt.Int(int(attempt)),
+			attrErrorType.String(errorType(err)),
+		))
+	})
+}
+
+// authenticate fetches a new token with the authenticator, in a span of its own
+func (m *Client) authenticate(ctx context.Context, authenticator Authenticator) (IAMToken, error) {
+	ctx, span := m.telemetry.tracer.Start(ctx, "watsonx authenticate",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attrGenAISystem.String(genAISystem),
+			attrWatsonxAuth.String(fmt.Sprintf("%T", authenticator)),
+		),
+	)
+	defer span.End()
+
+	token, err := authenticator.Authenticate(ctx, m.httpClient)
+	if err != nil {
+		span.SetAttributes(attrErrorType.String(errorType(err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return token, err
+}
+
+// errorType describes an error by its HTTP status, or its type
+func errorType(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return fmt.Sprintf("%T", err)
+}
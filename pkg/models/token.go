@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenRefreshSkew is how long before its expiration a token is refreshed
+	DefaultTokenRefreshSkew = 1 * time.Minute
+
+	// tokenFetchTimeout bounds a refresh that no caller is waiting for anymore
+	tokenFetchTimeout = 1 * time.Minute
+
+	// minBackgroundRefreshInterval keeps the background refresh from spinning when refreshes fail
+	minBackgroundRefreshInterval = 5 * time.Second
+
+	// neverExpiresRefreshInterval is how often the background refresh checks a token without expiration
+	neverExpiresRefreshInterval = 1 * time.Hour
+)
+
+// tokenFetcher fetches a new token, e.g. from an Authenticator
+type tokenFetcher func(ctx context.Context) (IAMToken, error)
+
+// tokenRefresh is a refresh in flight, shared by every caller that needs a token meanwhile
+type tokenRefresh struct {
+	done  chan struct{}
+	token IAMToken
+	err   error
+}
+
+// tokenSource caches a token and refreshes it before it expires.
+// It is safe for concurrent use; concurrent refreshes are coalesced into a single fetch.
+type tokenSource struct {
+	fetch tokenFetcher
+	skew  time.Duration
+
+	mu       sync.Mutex
+	token    IAMToken
+	fetched  bool // whether token holds a usable token
+	inflight *tokenRefresh
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newTokenSource(fetch tokenFetcher, skew time.Duration) *tokenSource {
+	return &tokenSource{
+		fetch: fetch,
+		skew:  skew,
+		stop:  make(chan struct{}),
+	}
+}
+
+// Token returns a valid token. A token expiring within the skew is refreshed in the background
+// while the call goes on with it; only a missing or expired token makes the call wait for IAM.
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	token, fetched := s.token, s.fetched
+	s.mu.Unlock()
+
+	if fetched && !token.expiresWithin(s.skew) {
+		return token.value, nil
+	}
+
+	if fetched && !token.Expired() {
+		// failures are left to the next call, which tries again until the token expires
+		s.startRefresh(ctx)
+		return token.value, nil
+	}
+
+	refreshed, err := s.Refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	return refreshed.value, nil
+}
+
+// Refresh fetches a new token, joining the refresh already in flight if there is one.
+// The fetch outlives a cancelled caller so that other callers waiting for it still get a token.
+func (s *tokenSource) Refresh(ctx context.Context) (IAMToken, error) {
+	refresh := s.startRefresh(ctx)
+
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return IAMToken{}, ctx.Err()
+	}
+}
+
+// startRefresh starts fetching a new token unless a refresh is already in flight, and returns it
+func (s *tokenSource) startRefresh(ctx context.Context) *tokenRefresh {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inflight != nil {
+		return s.inflight
+	}
+
+	refresh := &tokenRefresh{done: make(chan struct{})}
+	s.inflight = refresh
+
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tokenFetchTimeout)
+	go func() {
+		defer cancel()
+
+		token, err := s.fetch(fetchCtx)
+
+		s.mu.Lock()
+		if err == nil {
+			s.token, s.fetched = token, true
+		}
+		refresh.token, refresh.err = token, err
+		s.inflight = nil
+		s.mu.Unlock()
+
+		close(refresh.done)
+	}()
+
+	return refresh
+}
+
+// current returns the cached token value, without refreshing it
+func (s *tokenSource) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token.value
+}
+
+// Invalidate drops the token if it is still the rejected one, so that the next call refreshes it
+func (s *tokenSource) Invalidate(rejected string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.value == rejected {
+		s.token, s.fetched = IAMToken{}, false
+	}
+}
+
+// StartBackgroundRefresh refreshes the token ahead of its expiration until Close is called
+func (s *tokenSource) StartBackgroundRefresh() {
+	go func() {
+		for {
+			s.mu.Lock()
+			token := s.token
+			s.mu.Unlock()
+
+			wait := time.Until(token.expiration) - s.skew
+			if token.expiration.IsZero() {
+				// the token does not expire; only check back once in a while
+				wait = neverExpiresRefreshInterval
+			} else if wait < minBackgroundRefreshInterval {
+				wait = minBackgroundRefreshInterval
+			}
+
+			select {
+			case <-time.After(wait):
+				// failures are left to the next call, which refreshes on its own
+				s.Refresh(context.Background())
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background refresh
+func (s *tokenSource) Close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
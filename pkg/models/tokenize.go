@@ -0,0 +1,123 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const (
+	TokenizeEndpoint string = GenerationEndpoint + "/tokenization"
+)
+
+type TokenizePayload struct {
+	ProjectID  string              `json:"project_id,omitempty"`
+	SpaceID    string              `json:"space_id,omitempty"`
+	Model      string              `json:"model_id"`
+	Input      string              `json:"input"`
+	Parameters *TokenizeParameters `json:"parameters,omitempty"`
+}
+
+type TokenizeParameters struct {
+	ReturnTokens bool `json:"return_tokens"`
+}
+
+type TokenizeResult struct {
+	TokenCount int      `json:"token_count"`
+	Tokens     []string `json:"tokens,omitempty"`
+}
+
+type tokenizeResponse struct {
+	Model  string         `json:"model_id"`
+	Result TokenizeResult `json:"result"`
+}
+
+// Tokenize counts the tokens of the text with the model's tokenizer, returning the tokens themselves if returnTokens is set
+func (m *Client) Tokenize(model, text string, returnTokens bool, options ...TokenizeOption) (TokenizeResult, error) {
+	return m.TokenizeContext(context.Background(), model, text, returnTokens, options...)
+}
+
+// TokenizeContext is like Tokenize but binds the request to ctx
+func (m *Client) TokenizeContext(ctx context.Context, model, text string, returnTokens bool, options ...TokenizeOption) (TokenizeResult, error) {
+	if text == "" {
+		return TokenizeResult{}, errors.New("text cannot be empty")
+	}
+
+	opts := &TokenizeOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	projectID, spaceID, err := m.scope(opts.scope)
+	if err != nil {
+		return TokenizeResult{}, err
+	}
+
+	payload := TokenizePayload{
+		ProjectID:  projectID,
+		SpaceID:    spaceID,
+		Model:      model,
+		Input:      text,
+		Parameters: &TokenizeParameters{ReturnTokens: returnTokens},
+	}
+
+	usage := Usage{Operation: OperationTokenize, Model: model}
+	ctx, call := m.startCall(ctx, usage)
+	result, err := m.tokenizeRequest(ctx, payload)
+
+	usage.Err = err
+	call.end(usage)
+
+	return result, err
+}
+
+func (m *Client) tokenizeRequest(ctx context.Context, payload TokenizePayload) (TokenizeResult, error) {
+	req, err := m.newJSONRequest(ctx, http.MethodPost, TokenizeEndpoint, payload)
+	if err != nil {
+		return TokenizeResult{}, err
+	}
+
+	res, err := m.doRequest(req)
+	if err != nil {
+		return TokenizeResult{}, err
+	}
+	defer res.Body.Close()
+
+	var tokenizeRes tokenizeResponse
+
+	if err := json.NewDecoder(res.Body).Decode(&tokenizeRes); err != nil {
+		return TokenizeResult{}, err
+	}
+
+	return tokenizeRes.Result, nil
+}
+
+// TokenizeBatch tokenizes the texts with at most WithTokenizeConcurrency requests at once (DefaultBatchConcurrency by default).
+// The results are in the order of the texts. Failed texts have an empty result and are reported
+// in the returned error, whose BatchErrors carry their indexes.
+func (m *Client) TokenizeBatch(model string, texts []string, returnTokens bool, options ...TokenizeOption) ([]TokenizeResult, error) {
+	return m.TokenizeBatchContext(context.Background(), model, texts, returnTokens, options...)
+}
+
+// TokenizeBatchContext is like TokenizeBatch but binds the requests to ctx
+func (m *Client) TokenizeBatchContext(ctx context.Context, model string, texts []string, returnTokens bool, options ...TokenizeOption) ([]TokenizeResult, error) {
+	opts := &TokenizeOptions{}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	results := make([]TokenizeResult, len(texts))
+
+	err := runBatch(ctx, len(texts), int(opts.concurrency), func(ctx context.Context, i int) error {
+		result, err := m.TokenizeContext(ctx, model, texts[i], returnTokens, options...)
+		results[i] = result
+		return err
+	})
+
+	return results, err
+}
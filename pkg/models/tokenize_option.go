@@ -0,0 +1,31 @@
+package models
+
+type TokenizeOption func(*TokenizeOptions)
+
+type TokenizeOptions struct {
+	concurrency uint // Only used by TokenizeBatch
+	scope       callScope
+}
+
+// WithTokenizeConcurrency limits how many requests TokenizeBatch sends at once
+func WithTokenizeConcurrency(concurrency uint) TokenizeOption {
+	return func(opts *TokenizeOptions) {
+		opts.concurrency = concurrency
+	}
+}
+
+// WithTokenizeProjectID runs the call in the given project instead of the client's project or space;
+// it cannot be combined with WithTokenizeSpaceID
+func WithTokenizeProjectID(projectID WatsonxProjectID) TokenizeOption {
+	return func(opts *TokenizeOptions) {
+		opts.scope.projectID = projectID
+	}
+}
+
+// WithTokenizeSpaceID runs the call in the given deployment space instead of the client's project or space;
+// it cannot be combined with WithTokenizeProjectID
+func WithTokenizeSpaceID(spaceID WatsonxSpaceID) TokenizeOption {
+	return func(opts *TokenizeOptions) {
+		opts.scope.spaceID = spaceID
+	}
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	FunctionToolType = "function"
+
+	DefaultMaxToolIterations = 10
+)
+
+type ToolChoiceOption = string
+
+const (
+	ToolChoiceNone     ToolChoiceOption = "none"     // Model never calls tools
+	ToolChoiceAuto     ToolChoiceOption = "auto"     // Model decides whether to call tools
+	ToolChoiceRequired ToolChoiceOption = "required" // Model must call at least one tool
+)
+
+// ErrMaxToolIterations is returned when the model keeps calling tools past the iteration limit
+var ErrMaxToolIterations = errors.New("maximum tool iterations reached")
+
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"` // JSON schema of the arguments
+}
+
+// ToolChoice forces the model to call a specific function
+type ToolChoice struct {
+	Type     string             `json:"type"`
+	Function ToolChoiceFunction `json:"function"`
+}
+
+type ToolChoiceFunction struct {
+	Name string `json:"name"`
+}
+
+// NewFunctionTool creates a function tool; parameters is the JSON schema of its arguments,
+// e.g. a map[string]any or a json.RawMessage
+func NewFunctionTool(name, description string, parameters any) Tool {
+	return Tool{
+		Type: FunctionToolType,
+		Function: ToolFunction{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}
+}
+
+// DecodeArguments unmarshals the JSON encoded arguments of the call into v
+func (tc ToolCall) DecodeArguments(v any) error {
+	return json.Unmarshal([]byte(tc.Function.Arguments), v)
+}
+
+// ToolHandler executes a tool call given its JSON encoded arguments and returns the tool result
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// ToolRegistry holds the tools offered to the model and the Go handlers that execute them
+type ToolRegistry struct {
+	tools    []Tool
+	handlers map[string]ToolHandler
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		handlers: make(map[string]ToolHandler),
+	}
+}
+
+// Register adds a tool and its handler; registering the same name again replaces the handler
+func (r *ToolRegistry) Register(tool Tool, handler ToolHandler) {
+	name := tool.Function.Name
+	if _, ok := r.handlers[name]; !ok {
+		r.tools = append(r.tools, tool)
+	} else {
+		for i := range r.tools {
+			if r.tools[i].Function.Name == name {
+				r.tools[i] = tool
+			}
+		}
+	}
+	r.handlers[name] = handler
+}
+
+// Tools returns the registered tool definitions
+func (r *ToolRegistry) Tools() []Tool {
+	return r.tools
+}
+
+// Dispatch runs the handler of each call and returns the tool messages with their results.
+// Handler failures and unknown tools are reported to the model in the message content.
+func (r *ToolRegistry) Dispatch(ctx context.Context, calls []ToolCall) ([]ChatMessage, error) {
+	messages := make([]ChatMessage, 0, len(calls))
+
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		handler, ok := r.handlers[call.Function.Name]
+		if !ok {
+			messages = append(messages, ToolMessage(call.ID, fmt.Sprintf("error: unknown tool %q", call.Function.Name)))
+			continue
+		}
+
+		content, err := handler(ctx, call.Function.Arguments)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			content = "error: " + err.Error()
+		}
+
+		messages = append(messages, ToolMessage(call.ID, content))
+	}
+
+	return messages, nil
+}
+
+// ChatWithTools chats with the registered tools available, executing every tool call the model makes
+// and sending the results back until the model answers without calling a tool.
+// Returns the final result and the whole conversation, including tool calls and results.
+func (m *Client) ChatWithTools(model string, messages []ChatMessage, registry *ToolRegistry, options ...ChatOption) (ChatResult, []ChatMessage, error) {
+	return m.ChatWithToolsContext(context.Background(), model, messages, registry, options...)
+}
+
+// ChatWithToolsContext is like ChatWithTools but binds the requests and tool handlers to ctx
+func (m *Client) ChatWithToolsContext(ctx context.Context, model string, messages []ChatMessage, registry *ToolRegistry, options ...ChatOption) (ChatResult, []ChatMessage, error) {
+	if registry == nil {
+		return ChatResult{}, nil, errors.New("tool registry cannot be nil")
+	}
+
+	opts := &ChatOptions{maxToolIterations: DefaultMaxToolIterations}
+	for _, opt := range options {
+		if opt != nil {
+			opt(opts)
+		}
+	}
+
+	// cap the slice so that appending never writes into the caller's array
+	options = append(options[:len(options):len(options)], WithChatTools(registry.Tools()...))
+	conversation := append([]ChatMessage(nil), messages...)
+
+	for i := uint(0); i < opts.maxToolIterations; i++ {
+		result, err := m.ChatContext(ctx, model, conversation, options...)
+		if err != nil {
+			return ChatResult{}, conversation, err
+		}
+
+		message := result.Choices[0].Message
+		conversation = append(conversation, message)
+
+		if len(message.ToolCalls) == 0 {
+			return result, conversation, nil
+		}
+
+		toolMessages, err := registry.Dispatch(ctx, message.ToolCalls)
+		if err != nil {
+			return result, conversation, err
+		}
+		conversation = append(conversation, toolMessages...)
+	}
+
+	return ChatResult{}, conversation, ErrMaxToolIterations
+}
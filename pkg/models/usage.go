@@ -0,0 +1,250 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type UsageOperation = string
+
+const (
+	OperationTextCompletion UsageOperation = "text_completion" // GenerateText and its variants
+	OperationChat           UsageOperation = "chat"
+	OperationEmbeddings     UsageOperation = "embeddings"
+	OperationRerank         UsageOperation = "rerank"
+	OperationTokenize       UsageOperation = "tokenize"
+	OperationListModels     UsageOperation = "list_models" // ListFoundationModels and the calls built on it
+)
+
+// Usage describes one request to watsonx, as reported to a UsageRecorder
+type Usage struct {
+	Operation    UsageOperation
+	Stream       bool
+	Model        string
+	DeploymentID string // Set instead of Model for deployments
+
+	InputTokens     int
+	GeneratedTokens int
+	StopReason      string // The stop or finish reason of the first result, if any
+
+	Latency time.Duration // Until the response, or the end of the stream
+	Err     error         // The error of the request, or of the stream once ended
+	Tags    map[string]string
+}
+
+// UsageRecorder is told about every generation, stream, chat, embedding, rerank, tokenize and model listing
+// request the client sends, successful or not, see WithUsageRecorder. Embeddings served from an EmbeddingCache are not requests.
+// Implementations must be safe for concurrent use and should return quickly.
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, usage Usage)
+}
+
+// UsageRecorderFunc adapts a function to a UsageRecorder
+type UsageRecorderFunc func(ctx context.Context, usage Usage)
+
+func (f UsageRecorderFunc) RecordUsage(ctx context.Context, usage Usage) {
+	f(ctx, usage)
+}
+
+// usageTagsKey is the context key of the usage tags of a call
+type usageTagsKey struct{}
+
+// ContextWithUsageTags returns a copy of ctx whose calls report the tags with their usage,
+// e.g. to attribute them to a tenant. The tags are added to those already carried by ctx.
+func ContextWithUsageTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := map[string]string{}
+	for key, value := range UsageTagsFromContext(ctx) {
+		merged[key] = value
+	}
+	for key, value := range tags {
+		merged[key] = value
+	}
+
+	return context.WithValue(ctx, usageTagsKey{}, merged)
+}
+
+// UsageTagsFromContext returns the usage tags carried by ctx
+func UsageTagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(usageTagsKey{}).(map[string]string)
+	return tags
+}
+
+// call follows one request to watsonx, from its span to its usage report
+type call struct {
+	m     *Client
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+	usage Usage
+}
+
+// startCall starts the span of a request; the returned context carries it and should be used for the request
+func (m *Client) startCall(ctx context.Context, usage Usage) (context.Context, *call) {
+	ctx, span := m.startSpan(ctx, usage)
+	return ctx, &call{m: m, ctx: ctx, span: span, start: time.Now(), usage: usage}
+}
+
+// firstChunk records the time to the first chunk of a stream
+func (c *call) firstChunk() {
+	c.m.recordFirstChunk(c.ctx, c.span, c.usage, time.Since(c.start))
+}
+
+// end charges the tokens of the request to the rate limiter, ends its span and reports it to the usage recorder
+func (c *call) end(usage Usage) {
+	usage.Latency = time.Since(c.start)
+	usage.Tags = UsageTagsFromContext(c.ctx)
+
+	if c.m.limiter != nil {
+		c.m.limiter.consume(usage.InputTokens + usage.GeneratedTokens)
+	}
+
+	c.m.endSpan(c.ctx, c.span, usage)
+
+	if c.m.usageRecorder != nil {
+		c.m.usageRecorder.RecordUsage(c.ctx, usage)
+	}
+}
+
+// ModelPrice is the price of a model per million tokens
+type ModelPrice struct {
+	InputPerMillion     float64
+	GeneratedPerMillion float64
+}
+
+// PriceTable maps model and deployment IDs to their price
+type PriceTable map[string]ModelPrice
+
+// Cost estimates the cost of the tokens; models missing from the table cost nothing
+func (p PriceTable) Cost(model string, inputTokens, generatedTokens int) float64 {
+	price, ok := p[model]
+	if !ok {
+		return 0
+	}
+	return (float64(inputTokens)*price.InputPerMillion + float64(generatedTokens)*price.GeneratedPerMillion) / 1e6
+}
+
+// UsageTotals adds up the usage of many requests
+type UsageTotals struct {
+	Requests        int
+	Errors          int
+	InputTokens     int
+	GeneratedTokens int
+	Latency         time.Duration // Summed over the requests
+	EstimatedCost   float64
+}
+
+func (t *UsageTotals) add(usage Usage, cost float64) {
+	t.Requests++
+	if usage.Err != nil {
+		t.Errors++
+	}
+	t.InputTokens += usage.InputTokens
+	t.GeneratedTokens += usage.GeneratedTokens
+	t.Latency += usage.Latency
+	t.EstimatedCost += cost
+}
+
+// UsageAggregator is an in-memory UsageRecorder totalling the usage per model and per tag
+type UsageAggregator struct {
+	mu     sync.Mutex
+	prices PriceTable
+	total  UsageTotals
+	models map[string]*UsageTotals
+	tags   map[string]map[string]*UsageTotals // tag key, then tag value
+}
+
+// NewUsageAggregator creates an aggregator estimating costs with the prices, which may be nil
+func NewUsageAggregator(prices PriceTable) *UsageAggregator {
+	return &UsageAggregator{
+		prices: prices,
+		models: map[string]*UsageTotals{},
+		tags:   map[string]map[string]*UsageTotals{},
+	}
+}
+
+func (a *UsageAggregator) RecordUsage(ctx context.Context, usage Usage) {
+	model := usage.Model
+	if model == "" {
+		model = usage.DeploymentID
+	}
+	cost := a.prices.Cost(model, usage.InputTokens, usage.GeneratedTokens)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.total.add(usage, cost)
+
+	if a.models[model] == nil {
+		a.models[model] = &UsageTotals{}
+	}
+	a.models[model].add(usage, cost)
+
+	for key, value := range usage.Tags {
+		if a.tags[key] == nil {
+			a.tags[key] = map[string]*UsageTotals{}
+		}
+		if a.tags[key][value] == nil {
+			a.tags[key][value] = &UsageTotals{}
+		}
+		a.tags[key][value].add(usage, cost)
+	}
+}
+
+// Total returns the usage of all requests
+func (a *UsageAggregator) Total() UsageTotals {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.total
+}
+
+// ByModel returns the usage per model or deployment ID
+func (a *UsageAggregator) ByModel() map[string]UsageTotals {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return copyTotals(a.models)
+}
+
+// ByTag returns the usage per value of the tag, e.g. per tenant
+func (a *UsageAggregator) ByTag(key string) map[string]UsageTotals {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return copyTotals(a.tags[key])
+}
+
+// Models returns the models and deployments used so far, sorted
+func (a *UsageAggregator) Models() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	models := make([]string, 0, len(a.models))
+	for model := range a.models {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// Reset clears the totals, e.g. after exporting them
+func (a *UsageAggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.total = UsageTotals{}
+	a.models = map[string]*UsageTotals{}
+	a.tags = map[string]map[string]*UsageTotals{}
+}
+
+func copyTotals(totals map[string]*UsageTotals) map[string]UsageTotals {
+	copied := make(map[string]UsageTotals, len(totals))
+	for key, t := range totals {
+		copied[key] = *t
+	}
+	return copied
+}
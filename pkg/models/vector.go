@@ -0,0 +1,157 @@
+package models
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Float is the element type of an embedding vector
+type Float interface {
+	~float32 | ~float64
+}
+
+// Similarity scores how alike two vectors of the same length are, higher being more alike
+type Similarity[T Float] func(a, b []T) float64
+
+// Dot returns the dot product of two vectors. It panics if their lengths differ.
+func Dot[T Float](a, b []T) float64 {
+	if len(a) != len(b) {
+		panic("models: vectors of different lengths")
+	}
+
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors, or 0 if either is zero.
+// It panics if their lengths differ.
+func CosineSimilarity[T Float](a, b []T) float64 {
+	normA, normB := Norm(a), Norm(b)
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return Dot(a, b) / (normA * normB)
+}
+
+// Norm returns the L2 norm of a vector
+func Norm[T Float](v []T) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// Normalize scales a vector in place to a unit L2 norm, so that Dot gives the cosine similarity.
+// A zero vector is left unchanged.
+func Normalize[T Float](v []T) []T {
+	norm := Norm(v)
+	if norm == 0 {
+		return v
+	}
+
+	for i := range v {
+		v[i] = T(float64(v[i]) / norm)
+	}
+	return v
+}
+
+// Match is a candidate vector found by TopK, at its index among the candidates
+type Match struct {
+	Index int
+	Score float64
+}
+
+// TopK returns the k candidates most similar to the query, most similar first.
+// Empty candidates, such as the failed results of EmbedDocumentsBatched, are skipped.
+func TopK[T Float](query []T, candidates [][]T, k int, similarity Similarity[T]) []Match {
+	if k <= 0 {
+		return nil
+	}
+
+	matches := make(matchHeap, 0, min(k, len(candidates))+1)
+	for i, candidate := range candidates {
+		if len(candidate) == 0 {
+			continue
+		}
+
+		score := similarity(query, candidate)
+		if len(matches) == k && score <= matches[0].Score {
+			continue
+		}
+
+		heap.Push(&matches, Match{Index: i, Score: score})
+		if len(matches) > k {
+			heap.Pop(&matches)
+		}
+	}
+
+	sorted := make([]Match, len(matches))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(&matches).(Match)
+	}
+	return sorted
+}
+
+// TopKResults returns the k results most similar to the query by cosine similarity, most similar first.
+// The results are compared as float32 if the query was embedded with WithEmbeddingFloat32.
+func TopKResults(query EmbeddingResult, results []EmbeddingResult, k int) []Match {
+	if query.Embedding32 != nil {
+		candidates := make([][]float32, len(results))
+		for i, result := range results {
+			candidates[i] = result.Float32()
+		}
+		return TopK(query.Embedding32, candidates, k, CosineSimilarity[float32])
+	}
+
+	candidates := make([][]float64, len(results))
+	for i, result := range results {
+		candidates[i] = result.Float64()
+	}
+	return TopK(query.Embedding, candidates, k, CosineSimilarity[float64])
+}
+
+// Float64 returns the embedding as float64, converting Embedding32 if needed
+func (r EmbeddingResult) Float64() []float64 {
+	if r.Embedding != nil || r.Embedding32 == nil {
+		return r.Embedding
+	}
+
+	v := make([]float64, len(r.Embedding32))
+	for i, x := range r.Embedding32 {
+		v[i] = float64(x)
+	}
+	return v
+}
+
+// Float32 returns the embedding as float32, converting Embedding if needed
+func (r EmbeddingResult) Float32() []float32 {
+	if r.Embedding32 != nil || r.Embedding == nil {
+		return r.Embedding32
+	}
+
+	v := make([]float32, len(r.Embedding))
+	for i, x := range r.Embedding {
+		v[i] = float32(x)
+	}
+	return v
+}
+
+// matchHeap is a min-heap of matches by score
+type matchHeap []Match
+
+func (h matchHeap) Len() int           { return len(h) }
+func (h matchHeap) Less(i, j int) bool { return h[i].Score < h[j].Score }
+func (h matchHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *matchHeap) Push(x any)        { *h = append(*h, x.(Match)) }
+
+func (h *matchHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}